@@ -2,71 +2,174 @@ package main
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/alecthomas/kong"
 	"github.com/onemorebsmith/blackjack-solver/cmd"
+	blackjack "github.com/onemorebsmith/blackjack-solver/src"
 	"github.com/onemorebsmith/blackjack-solver/src/blackjack/strategies"
 )
 
+type SimCommand struct {
+	OutcomeHours float32 `name:"outcome-hours" help:"Report the distribution of results after this many hours of play, 0 to skip"`
+	Trials       int     `name:"trials" default:"10000" help:"Bootstrap trials for the outcome distribution"`
+}
+
+type RampCommand struct {
+	MaxSpread float32 `name:"max-spread" default:"12"`
+	Objective string  `name:"objective" enum:"kelly,score" default:"kelly"`
+}
+
+type AnalyzeCountCommand struct {
+	System string `name:"system" enum:"hilo,ko,red7" default:"hilo" help:"Built in count system to analyze"`
+	Tags   string `name:"tags" help:"Custom tags to analyze instead, e.g. 2:1;3:1;4:1;5:1;6:1;T:-1;A:-1"`
+}
+
+type EORCommand struct {
+	Plays    []string `name:"play" help:"Plays to compute effects of removal for, e.g. 16v10,insurance"`
+	AllPlays bool     `name:"all-plays" help:"Compute effects of removal for every index play"`
+}
+
+type IndicesCommand struct {
+	Plays []string `name:"play" help:"Plays to generate indices for, all the index plays when empty"`
+	Out   string   `name:"out" help:"File to save the index table to, loadable with --indices"`
+}
+
+type ErrorsCommand struct{}
+
+type CamouflageCommand struct{}
+
+type AceSideCommand struct{}
+
+type TeamCommand struct {
+	Tables       int     `name:"tables" default:"3" help:"Tables with a spotter each"`
+	SpotterUnits float32 `name:"spotter-units" default:"1" help:"The spotters' flat bet in units"`
+	CallIn       int     `name:"call-in" default:"2" help:"True count the spotter calls the big player in at"`
+	Leave        int     `name:"leave" default:"1" help:"The big player leaves once the true count drops below this"`
+	Travel       int     `name:"travel" default:"1" help:"Rounds dealt before the big player reaches the table"`
+}
+
+type SessionCommand struct {
+	Sessions int     `name:"sessions" default:"1000" help:"Sessions to sim"`
+	Hours    float32 `name:"hours" default:"4" help:"Length of each session"`
+	StopLoss float32 `name:"stop-loss" help:"End a session once down this many units, 0 for none"`
+	WinGoal  float32 `name:"win-goal" help:"End a session once up this many units, 0 for none"`
+}
+
+type CSMCommand struct{}
+
+type TrackCommand struct{}
+
+type SequenceCommand struct {
+	Shuffles []string `name:"shuffles" sep:";" default:"riffle;riffle,riffle;riffle,riffle,riffle;riffle,riffle,strip:5,riffle,cut" help:"Shuffles to compare, ; separated"`
+}
+
 type CommandLine struct {
+	Sim          SimCommand          `cmd:"" default:"withargs" help:"Simulate the configured game"`
+	Ramp         RampCommand         `cmd:"" help:"Generate an optimal bet ramp for the configured game"`
+	Indices      IndicesCommand      `cmd:"" name:"indices" help:"Generate playing indices for the configured game by simulation"`
+	Errors       ErrorsCommand       `cmd:"" name:"errors" help:"Attribute the EV lost to each type of player error"`
+	CompareCSM   CSMCommand          `cmd:"" name:"compare-csm" help:"Compare the configured game dealt from a shoe and from a continuous shuffler"`
+	Track        TrackCommand        `cmd:"" name:"track" help:"Compare counting and shuffle tracking against the configured shuffle"`
+	Sequence     SequenceCommand     `cmd:"" name:"sequence" help:"Compare ace sequencing and counting as the shuffle improves"`
+	Session      SessionCommand      `cmd:"" name:"session" help:"Simulate sessions and report the distribution of their results"`
+	Team         TeamCommand         `cmd:"" name:"team" help:"Simulate spotters calling in a big player across tables"`
+	Camouflage   CamouflageCommand   `cmd:"" name:"camouflage" help:"Cost each camouflage betting rule against the raw spread"`
+	CompareAce   AceSideCommand      `cmd:"" name:"ace-side" help:"Compare HiLo with and without the ace side count"`
+	EOR          EORCommand          `cmd:"" name:"eor" help:"Compute the effects of removal of each card on the configured game"`
+	AnalyzeCount AnalyzeCountCommand `cmd:"" name:"analyze-count" help:"Compute the betting correlation, playing efficiency and insurance correlation of a count system"`
+
 	Decks         int     `name:"decks" default:"6"`
 	H17           bool    `name:"h17" default:"false"`
 	RSA           bool    `name:"rsa"`
 	RoundsPerHour float32 `name:"rph" default:"100"`
 	DAS           bool    `name:"das"`
 	Shoes         int     `name:"shoes" default:"6"`
-	Pen           float32 `name:"pen" default:"1.2"`
+	Pen           float32 `name:"pen" default:"1.2" help:"Decks cut off"`
+	PenDealt      float32 `name:"pen-dealt" help:"Share of the shoe dealt before the cut card, overrides --pen"`
+	CutCardSD     float32 `name:"cut-sd" help:"Standard deviation of the cut card placement, in cards"`
+	ExtraRounds   int     `name:"extra-rounds" help:"Rounds dealt after the one the cut card comes out in"`
+	Burn          int     `name:"burn" help:"Cards burned after each shuffle"`
+	BurnVisible   bool    `name:"burn-visible" help:"Show the burn cards to the table"`
+	HoleCard      float64 `name:"hole-card" help:"Chance the dealer flashes the hole card in a round"`
 	Spread        string  `name:"spread"`
 	Strategy      string  `name:"strat" default:"hilo"`
 	Splits        int     `name:"splits" default:"3"`
-	Unit          float32 `name:"unit" default:"25"`
-}
-
-func parseSpread(s string) (map[int]strategies.BidStrategy, error) {
-	split := strings.Split(s, ";")
-	created := map[int]strategies.BidStrategy{}
-	for _, s := range split {
-		parts := strings.Split(s, ":")
-		if len(parts) < 2 {
-			return nil, fmt.Errorf("invalid spread format for key %s", s)
-		}
-		tc, err := strconv.ParseInt(parts[0], 10, 32)
-		if err != nil {
-			return nil, fmt.Errorf("failed parsing %s as number", parts[0])
-		}
-		bid, err := strconv.ParseInt(parts[1], 10, 32)
-		if err != nil {
-			return nil, fmt.Errorf("failed parsing %s as number", parts[0])
-		}
-		hands := 1
-		if len(parts) == 3 {
-			parsedHands, err := strconv.ParseInt(parts[2], 10, 32)
-			if err != nil {
-				return nil, fmt.Errorf("failed parsing %s as number", parts[0])
-			}
-			hands = int(parsedHands)
-		}
-
-		created[int(tc)] = strategies.BidStrategy{Hands: hands, Units: float32(bid)}
-	}
-	return created, nil
+	Unit          float32 `name:"unit" default:"25" help:"Unit size in currency"`
+	TableMin      float32 `name:"table-min" help:"Table minimum in currency, defaults to the unit size"`
+	TableMax      float32 `name:"table-max" help:"Table maximum in currency, 0 for no max"`
+	Chip          float32 `name:"chip" help:"Smallest chip in currency, bets are rounded to it"`
+	Bankroll      float32 `name:"bankroll" default:"10000" help:"Starting bankroll in units"`
+	Kelly         float32 `name:"kelly" help:"Size bets as this fraction of kelly instead of using the spread"`
+	KellyShoes    int     `name:"kelly-shoes" help:"Fit the kelly edge model to a flatbet sim of this many shoes of the game, 0 uses rough 6 deck HiLo figures"`
+	Wong          bool    `name:"wong" help:"Back count the shoe and only play at favorable counts"`
+	WongIn        int     `name:"wong-in" default:"2" help:"True count to start playing at when wonging"`
+	WongOut       int     `name:"wong-out" default:"0" help:"Stop playing once the true count drops below this"`
+	DeckEstimate  string  `name:"deck-est" enum:"exact,quarter,half,full" default:"exact" help:"How finely decks remaining are estimated"`
+	TCRounding    string  `name:"tc-round" enum:"truncate,floor,round" default:"truncate" help:"How the true count is converted to a whole number"`
+	AceSide       bool    `name:"ace-side" help:"Keep an ace side count and adjust bets for it"`
+	AceAdjust     float32 `name:"ace-adjust" default:"1" help:"Running count adjustment per surplus ace"`
+	KeyCount      int     `name:"key-count" default:"-4" help:"Running count to start raising bets at for unbalanced counts"`
+	Pivot         int     `name:"pivot" default:"4" help:"Running count to reach the max bet at for unbalanced counts"`
+	MaxUnits      float32 `name:"max-units" default:"12" help:"Bet at the pivot for unbalanced counts, and per spot on a sequenced ace"`
+	IndexTable    string  `name:"indices" help:"Index table file to play deviations from"`
+	ActionErrors  float64 `name:"action-errors" help:"Chance a playing decision is swapped for another legal one"`
+	Miscounts     float64 `name:"miscounts" help:"Chance each card is miscounted by +/-1"`
+	TCNoise       float64 `name:"tc-noise" help:"Standard deviation of the player's true count estimate"`
+	Seed          uint64  `name:"seed" help:"Seed for the shuffles so runs can be repeated, random when 0"`
+	MaxRaise      float32 `name:"max-raise" help:"Largest ratio a bet can be raised by between hands"`
+	Parlay        bool    `name:"parlay" help:"Only raise bets after a win, by at most the winnings"`
+	MaxDrop       float32 `name:"max-drop" help:"Largest ratio a bet can be dropped by after a loss"`
+	OffCount      float64 `name:"off-count" help:"Chance of a random bet regardless of the count"`
+	CSM           bool    `name:"csm" help:"Deal from a continuous shuffling machine"`
+	CSMReservoir  int     `name:"csm-reservoir" default:"10" help:"Cards the continuous shuffler holds out ready to deal"`
+	ShuffleRounds float32 `name:"shuffle-rounds" help:"Table time a hand shuffle takes, in rounds"`
+	Shuffle       string  `name:"shuffle" help:"The dealer's shuffle procedure, e.g. 'zone:52(riffle,strip:4,riffle),cut'. Random when empty"`
+	TrackSegment  int     `name:"track-segment" default:"26" help:"Cards per segment a shuffle tracker follows"`
+	TrackWindow   int     `name:"track-window" default:"26" help:"Cards ahead a shuffle tracker bets on"`
+	SeqKeys       int     `name:"seq-keys" default:"6" help:"Aces an ace sequencer memorizes a shoe"`
+	SeqWindow     int     `name:"seq-window" default:"3" help:"Cards after its key cards an ace is expected within"`
+	SeqSpots      int     `name:"seq-spots" default:"3" help:"Most spots played to steer a sequenced ace"`
+	Infinite      bool    `name:"infinite" help:"Deal from an infinite deck, every card drawn independently"`
+	Game          string  `name:"game" default:"standard" enum:"standard,spanish21" help:"The game dealt, spanish21 deals 48 card decks with its bonuses, surrender and double rescue. --h17, --das and --rsa still apply"`
+	Deck          string  `name:"deck" help:"The cards in each deck, standard, spanish or changes to a standard deck like '-10,A:2'"`
 }
 
 func main() {
 	var commandLine CommandLine
-	_ = kong.Parse(&commandLine)
-	bidspread, err := parseSpread(commandLine.Spread)
+	ctx := kong.Parse(&commandLine)
+	bidspread, err := strategies.ParseBidspread(commandLine.Spread)
+	if err != nil {
+		panic(err)
+	}
+
+	estimation, err := strategies.ParseDeckEstimation(commandLine.DeckEstimate)
 	if err != nil {
 		panic(err)
 	}
+	rounding, err := strategies.ParseTrueCountRounding(commandLine.TCRounding)
+	if err != nil {
+		panic(err)
+	}
+
+	indices := []blackjack.Index{}
+	if commandLine.IndexTable != "" {
+		indices, err = blackjack.LoadIndexTable(commandLine.IndexTable)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	tableMin := commandLine.TableMin
+	if tableMin <= 0 {
+		tableMin = commandLine.Unit
+	}
 
 	strategy := commandLine.Strategy
 	if commandLine.Strategy == "" {
 		strategy = "flatbet"
 	}
-	cmd.Run(cmd.BJConfig{
+	cfg := cmd.BJConfig{
 		Decks:         commandLine.Decks,
 		IsH17:         commandLine.H17,
 		IsDAS:         commandLine.DAS,
@@ -77,5 +180,140 @@ func main() {
 		Bidspread:     bidspread,
 		RoundsPerHour: commandLine.RoundsPerHour,
 		Strategy:      strings.ToLower(strategy),
-	})
+		Bankroll:      commandLine.Bankroll,
+		KellyFraction: commandLine.Kelly,
+		KellyShoes:    commandLine.KellyShoes,
+		UnitSize:      commandLine.Unit,
+		TableMin:      tableMin,
+		TableMax:      commandLine.TableMax,
+		ChipSize:      commandLine.Chip,
+		Wonging:       commandLine.Wong,
+		WongInTC:      commandLine.WongIn,
+		WongOutTC:     commandLine.WongOut,
+		TCMethod:      strategies.TrueCountMethod{Estimation: estimation, Rounding: rounding},
+		AceSideCount:  commandLine.AceSide,
+		AceAdjustment: commandLine.AceAdjust,
+		KeyCount:      commandLine.KeyCount,
+		Pivot:         commandLine.Pivot,
+		MaxUnits:      commandLine.MaxUnits,
+		Indices:       indices,
+		ActionErrors:  commandLine.ActionErrors,
+		Miscounts:     commandLine.Miscounts,
+		TCNoise:       commandLine.TCNoise,
+		Seed:          commandLine.Seed,
+		Camouflage: blackjack.Camouflage{
+			MaxIncreaseRatio: commandLine.MaxRaise,
+			ParlayOnly:       commandLine.Parlay,
+			MaxDecreaseRatio: commandLine.MaxDrop,
+			OffCountRate:     commandLine.OffCount,
+		},
+		CSM:           commandLine.CSM,
+		CSMReservoir:  commandLine.CSMReservoir,
+		ShuffleRounds: commandLine.ShuffleRounds,
+		PenDealt:      commandLine.PenDealt,
+		CutCardSD:     commandLine.CutCardSD,
+		ExtraRounds:   commandLine.ExtraRounds,
+		BurnCards:     commandLine.Burn,
+		BurnVisible:   commandLine.BurnVisible,
+		HoleCard:      commandLine.HoleCard,
+		Shuffle:       commandLine.Shuffle,
+		TrackSegment:  commandLine.TrackSegment,
+		TrackWindow:   commandLine.TrackWindow,
+		SeqKeys:       commandLine.SeqKeys,
+		SeqWindow:     commandLine.SeqWindow,
+		SeqSpots:      commandLine.SeqSpots,
+		Infinite:      commandLine.Infinite,
+		Deck:          commandLine.Deck,
+		Game:          commandLine.Game,
+		OutcomeHours:  commandLine.Sim.OutcomeHours,
+		Trials:        commandLine.Sim.Trials,
+	}
+
+	switch ctx.Command() {
+	case "ramp":
+		ramp := commandLine.Ramp
+		objective := strategies.RampObjectiveKelly
+		if ramp.Objective == "score" {
+			objective = strategies.RampObjectiveSCORE
+		}
+		kelly := commandLine.Kelly
+		if kelly <= 0 {
+			kelly = 1
+		}
+		fmt.Println(cmd.GenerateBetRamp(cfg, strategies.BetRampParams{
+			UnitSize:        commandLine.Unit,
+			TableMin:        tableMin,
+			TableMax:        commandLine.TableMax,
+			MaxSpread:       ramp.MaxSpread,
+			Bankroll:        commandLine.Bankroll * commandLine.Unit,
+			KellyFraction:   kelly,
+			Objective:       objective,
+			SpotCorrelation: strategies.DefaultSpotCorrelation,
+		}))
+	case "indices":
+		plays := blackjack.IndexPlays
+		if len(commandLine.Indices.Plays) > 0 {
+			plays = []blackjack.Play{}
+			for _, name := range commandLine.Indices.Plays {
+				play, err := blackjack.FindPlay(name)
+				if err != nil {
+					panic(err)
+				}
+				plays = append(plays, play)
+			}
+		}
+		cmd.GenerateIndices(cfg, plays, commandLine.Indices.Out)
+	case "compare-csm":
+		cmd.CompareCSM(cfg)
+	case "track":
+		cmd.CompareTracking(cfg)
+	case "sequence":
+		cmd.CompareSequencing(cfg, commandLine.Sequence.Shuffles)
+	case "session":
+		session := commandLine.Session
+		cmd.RunSessions(cfg, session.Sessions, blackjack.SessionParams{
+			Hours:    session.Hours,
+			StopLoss: session.StopLoss,
+			WinGoal:  session.WinGoal,
+		})
+	case "team":
+		team := commandLine.Team
+		cmd.RunTeam(cfg, blackjack.TeamParams{
+			Tables:       team.Tables,
+			SpotterUnits: team.SpotterUnits,
+			CallInTC:     team.CallIn,
+			LeaveTC:      team.Leave,
+			TravelRounds: team.Travel,
+		})
+	case "camouflage":
+		cmd.CamouflageCosts(cfg)
+	case "ace-side":
+		cmd.AceSideCosts(cfg)
+	case "errors":
+		cmd.ErrorCosts(cfg)
+	case "eor":
+		plays := []blackjack.Play{}
+		if commandLine.EOR.AllPlays {
+			plays = blackjack.IndexPlays
+		}
+		for _, name := range commandLine.EOR.Plays {
+			play, err := blackjack.FindPlay(name)
+			if err != nil {
+				panic(err)
+			}
+			plays = append(plays, play)
+		}
+		cmd.EffectsOfRemoval(cfg, plays)
+	case "analyze-count":
+		system := strategies.CountSystems[commandLine.AnalyzeCount.System]
+		if commandLine.AnalyzeCount.Tags != "" {
+			system, err = strategies.ParseCountTags(commandLine.AnalyzeCount.Tags)
+			if err != nil {
+				panic(err)
+			}
+		}
+		cmd.AnalyzeCount(cfg, system)
+	default:
+		cmd.Run(cfg)
+	}
 }
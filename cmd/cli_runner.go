@@ -3,12 +3,14 @@ package cmd
 import (
 	"fmt"
 	"log"
+	"math/rand/v2"
 	"runtime"
 	"strings"
 	"sync"
 	"time"
 
 	blackjack "github.com/onemorebsmith/blackjack-solver/src"
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
 	"github.com/onemorebsmith/blackjack-solver/src/blackjack/strategies"
 )
 
@@ -23,10 +25,63 @@ type BJConfig struct {
 	RoundsPerHour float32                        `json:"rph"`
 	Bidspread     map[int]strategies.BidStrategy `json:"bidspread"`
 	Strategy      string                         `json:"strategy"`
+	Bankroll      float32                        `json:"bankroll"`
+	KellyFraction float32                        `json:"kelly"`
+	KellyShoes    int                            `json:"kellyShoes"` // calibration shoes the kelly edge model is fit to, 0 for the defaults
+	UnitSize      float32                        `json:"unit"`
+	TableMin      float32                        `json:"tableMin"`
+	TableMax      float32                        `json:"tableMax"`
+	ChipSize      float32                        `json:"chip"`
+	Wonging       bool                           `json:"wonging"`
+	WongInTC      int                            `json:"wongIn"`
+	WongOutTC     int                            `json:"wongOut"`
+	TCMethod      strategies.TrueCountMethod     `json:"tcMethod"`
+	AceSideCount  bool                           `json:"aceSideCount"`
+	AceAdjustment float32                        `json:"aceAdjustment"`
+	KeyCount      int                            `json:"keyCount"`
+	Pivot         int                            `json:"pivot"`
+	MaxUnits      float32                        `json:"maxUnits"`
+	Indices       []blackjack.Index              `json:"indices"`
+	ActionErrors  float64                        `json:"actionErrors"`
+	Miscounts     float64                        `json:"miscounts"`
+	TCNoise       float64                        `json:"tcNoise"`
+	Seed          uint64                         `json:"seed"`
+	Camouflage    blackjack.Camouflage           `json:"camouflage"`
+	OutcomeHours  float32                        `json:"outcomeHours"`
+	Trials        int                            `json:"trials"`
+	CSM           bool                           `json:"csm"`
+	CSMReservoir  int                            `json:"csmReservoir"`
+	ShuffleRounds float32                        `json:"shuffleRounds"`
+	PenDealt      float32                        `json:"penDealt"`
+	CutCardSD     float32                        `json:"cutCardSD"`
+	ExtraRounds   int                            `json:"extraRounds"`
+	BurnCards     int                            `json:"burnCards"`
+	BurnVisible   bool                           `json:"burnVisible"`
+	HoleCard      float64                        `json:"holeCard"`
+	Shuffle       string                         `json:"shuffle"`
+	TrackSegment  int                            `json:"trackSegment"`
+	TrackWindow   int                            `json:"trackWindow"`
+	SeqKeys       int                            `json:"seqKeys"`
+	SeqWindow     int                            `json:"seqWindow"`
+	SeqSpots      int                            `json:"seqSpots"`
+	Infinite      bool                           `json:"infinite"`
+	Deck          string                         `json:"deck"`
+	Game          string                         `json:"game"`
 }
 
+const defaultBankroll = 10000
+
 func (cfg BJConfig) BuildGameDescription() string {
 	game := fmt.Sprintf("%d deck ", cfg.Decks)
+	if cfg.Infinite {
+		game = "infinite deck "
+	}
+	if cfg.Deck != "" && cfg.Deck != "standard" {
+		game += fmt.Sprintf("(%s) ", cfg.Deck)
+	}
+	if cfg.Game == "spanish21" {
+		game += "Spanish 21 "
+	}
 	if cfg.IsH17 {
 		game += "H17 "
 	} else {
@@ -43,21 +98,118 @@ func (cfg BJConfig) BuildGameDescription() string {
 
 var threads = runtime.NumCPU()
 
-func Run(cfg BJConfig) {
-	start := time.Now()
+func newGameRules(cfg BJConfig) *blackjack.BlackjackGameRules {
 	bjRules := blackjack.NewBlackjackGameRules(blackjack.InitGame(blackjack.H17Rules, blackjack.H17Splits))
+	switch cfg.Game {
+	case "spanish21":
+		log.Println("playing Spanish 21")
+		bjRules = blackjack.NewSpanish21Game()
+	case "", "standard":
+	default:
+		log.Fatalf("unknown game '%s'", cfg.Game)
+	}
 	bjRules.SetDealerHitsSoft17(cfg.IsH17)
 	bjRules.SetDoubleAfterSplit(cfg.IsDAS)
 	bjRules.SetResplitAces(cfg.IsRSA)
 	bjRules.SetMaxPlayerSplits(cfg.MaxSplits)
 	bjRules.SetUseSimpleDeviations(false)
 	bjRules.SetPenetration(cfg.Penetration)
+	bjRules.SetPenetrationDealt(cfg.PenDealt)
+	bjRules.SetCutCardPlacement(cfg.CutCardSD, cfg.ExtraRounds)
+	bjRules.SetBurn(cfg.BurnCards, cfg.BurnVisible)
+	if cfg.HoleCard > 0 {
+		bjRules.SetHoleCardExposure(blackjack.NewHoleCardExposure(cfg.HoleCard))
+	}
+	if cfg.Shuffle != "" {
+		shuffler, err := core.ParseShuffle(cfg.Shuffle)
+		if err != nil {
+			log.Fatalf("invalid shuffle: %s", err)
+		}
+		log.Printf("shuffling by hand: %s", shuffler.ToString())
+		bjRules.SetShuffler(shuffler)
+	}
+	bjRules.SetUnitSize(cfg.UnitSize)
+	bjRules.SetTableLimits(cfg.TableMin, cfg.TableMax)
+	bjRules.SetChipSize(cfg.ChipSize)
+	if cfg.Wonging {
+		bjRules.SetWonging(cfg.WongInTC, cfg.WongOutTC)
+	}
+	bjRules.SetSeed(cfg.Seed)
+	bjRules.SetShuffleRounds(cfg.ShuffleRounds)
+	bjRules.SetInfiniteDeck(cfg.Infinite)
+	if cfg.Deck != "" {
+		deck, err := core.ParseDeckComposition(cfg.Deck)
+		if err != nil {
+			log.Fatalf("invalid deck: %s", err)
+		}
+		log.Printf("dealing %d card %s decks", deck.Size(), deck.ToString())
+		bjRules.SetDeck(deck)
+	}
+	if cfg.CSM {
+		bjRules.SetContinuousShuffle(cfg.CSMReservoir)
+	}
+	if cfg.ActionErrors > 0 || cfg.Miscounts > 0 || cfg.TCNoise > 0 {
+		log.Printf("player errors: %f action error rate, %f miscount rate, %f TC noise", cfg.ActionErrors, cfg.Miscounts, cfg.TCNoise)
+		bjRules.SetErrorModel(blackjack.NewErrorModel(cfg.ActionErrors, cfg.Miscounts, cfg.TCNoise))
+	}
+	if cfg.Camouflage.Enabled() {
+		camouflage := cfg.Camouflage
+		log.Printf("camouflage: %f max raise, parlay only %t, %f max drop, %f off count rate",
+			camouflage.MaxIncreaseRatio, camouflage.ParlayOnly, camouflage.MaxDecreaseRatio, camouflage.OffCountRate)
+		bjRules.SetCamouflage(&camouflage)
+	}
+	if len(cfg.Indices) > 0 {
+		deviations, err := blackjack.NewDeviations(cfg.Indices)
+		if err != nil {
+			log.Fatalf("invalid index table: %s", err)
+		}
+		log.Printf("playing %d count based deviations", len(cfg.Indices))
+		bjRules.SetDeviations(deviations)
+	}
 
-	log.Printf("simming %d shoes of %s w/ %f pen", cfg.ShoesToSim, cfg.BuildGameDescription(), cfg.Penetration)
 	switch cfg.Strategy {
 	case "hilo":
-		log.Println("using HiLo strategy")
-		bjRules.TrackingStrategy = strategies.InitHighLow(cfg.Bidspread)
+		if cfg.KellyFraction > 0 {
+			log.Printf("using HiLo strategy w/ %f kelly bet sizing", cfg.KellyFraction)
+		} else {
+			log.Println("using HiLo strategy")
+		}
+		log.Printf("true count w/ %s", cfg.TCMethod.ToString())
+		hl := strategies.InitHighLowWithBidder(newBidder(cfg))
+		hl.Method = cfg.TCMethod
+		if cfg.AceSideCount {
+			log.Printf("side counting aces, %f per surplus ace", cfg.AceAdjustment)
+			hl.AceSideCount = strategies.NewAceSideCount(cfg.AceAdjustment)
+		}
+		bjRules.TrackingStrategy = hl
+	case "ko", "red7":
+		system := strategies.KO
+		if cfg.Strategy == "red7" {
+			system = strategies.Red7
+		}
+		// an explicit spread is keyed on the running count, otherwise ramp from the key count to the pivot
+		bidder := strategies.NewBidspread(cfg.Bidspread)
+		if len(cfg.Bidspread) == 0 {
+			bidder = strategies.NewKeyCountBidspread(cfg.KeyCount, cfg.Pivot, strategies.BidStrategy{Hands: 1, Units: cfg.MaxUnits})
+			log.Printf("using %s strategy, key count %d, pivot %d, IRC %d", system.Name, cfg.KeyCount, cfg.Pivot, system.IRC(cfg.Decks))
+		} else {
+			log.Printf("using %s strategy, IRC %d", system.Name, system.IRC(cfg.Decks))
+		}
+		bjRules.TrackingStrategy = strategies.InitUnbalanced(system, cfg.Decks, bidder)
+	case "track":
+		log.Printf("using shuffle tracking strategy, %d card segments, %d cards ahead", cfg.TrackSegment, cfg.TrackWindow)
+		tracker := strategies.InitShuffleTracker(bjRules.Shuffler, cfg.Decks, cfg.TrackSegment, cfg.TrackWindow, newBidder(cfg))
+		tracker.Method = cfg.TCMethod
+		if !cfg.BurnVisible {
+			tracker.HiddenBurn = cfg.BurnCards
+		}
+		bjRules.TrackingStrategy = tracker
+	case "sequence":
+		log.Printf("using ace sequencing strategy, %d aces a shoe, %d card window, up to %d spots of %f units",
+			cfg.SeqKeys, cfg.SeqWindow, cfg.SeqSpots, cfg.MaxUnits)
+		hl := strategies.InitHighLowWithBidder(newBidder(cfg))
+		hl.Method = cfg.TCMethod
+		bjRules.TrackingStrategy = strategies.InitAceSequencing(hl, cfg.SeqKeys, cfg.SeqWindow, cfg.SeqSpots, cfg.MaxUnits)
 	case "flatbet":
 		log.Println("Using flatbet strategy")
 		bjRules.TrackingStrategy = strategies.InitFlatbetStrategy()
@@ -65,20 +217,86 @@ func Run(cfg BJConfig) {
 		log.Println("Using flatbet strategy")
 		bjRules.TrackingStrategy = strategies.InitFlatbetStrategy()
 	}
+	return bjRules
+}
+
+// newBidder sizes the counting strategies' bets off the spread, or as a share of
+// the bankroll when Kelly betting
+func newBidder(cfg BJConfig) strategies.Bidder {
+	if cfg.KellyFraction <= 0 {
+		return strategies.NewBidspread(cfg.Bidspread)
+	}
+	kelly := strategies.NewKellyBidder(cfg.KellyFraction, 1, 0)
+	if cfg.UnitSize > 0 {
+		kelly.MinBet = cfg.TableMin / cfg.UnitSize
+		kelly.MaxBet = cfg.TableMax / cfg.UnitSize
+		kelly.ChipSize = cfg.ChipSize / cfg.UnitSize
+	}
+	if cfg.KellyShoes > 0 {
+		calibration := cfg
+		calibration.ShoesToSim = cfg.KellyShoes
+		kelly.Calibrate(calibrate(calibration).TCEstimates())
+	}
+	log.Printf("kelly edge %f at TC 0, %f per TC, variance %f", kelly.BaseEdge, kelly.EdgePerTC, kelly.Variance)
+	return kelly
+}
+
+// calibrate runs a flatbet HiLo sim of the configured game, its per count
+// results size the bets of the ramp and of kelly betting
+func calibrate(cfg BJConfig) blackjack.GameResults {
+	cfg.Strategy = "hilo"
+	cfg.Bidspread = map[int]strategies.BidStrategy{}
+	cfg.KellyFraction = 0
+	log.Printf("calibrating %d shoes of %s w/ %f pen", cfg.ShoesToSim, cfg.BuildGameDescription(), cfg.Penetration)
+	return blackjack.AggregateResults(simulate(cfg, newGameRules(cfg))...)
+}
 
+// simulate splits the shoes across all cpus, returning the results per thread
+func simulate(cfg BJConfig, bjRules *blackjack.BlackjackGameRules) []blackjack.GameResults {
 	overallResults := make([]blackjack.GameResults, threads)
+	errs := make([]error, threads)
 	wg := sync.WaitGroup{}
 
 	shoesPerThread := cfg.ShoesToSim / threads
+	bankroll := cfg.Bankroll
+	if bankroll <= 0 {
+		bankroll = defaultBankroll
+	}
 
 	for i := 0; i < threads; i++ {
 		wg.Add(1)
 		go func(idx int) {
 			defer wg.Done()
-			overallResults[idx] = blackjack.PlayGame(*bjRules, cfg.Decks, shoesPerThread, 10000, cfg.RoundsPerHour)
+			rules := *bjRules
+			if rules.Seed != 0 {
+				// each thread plays its own shoes, the same ones every run
+				rules.Seed += uint64(idx)
+			}
+			overallResults[idx], errs[idx] = blackjack.PlayGame(rules, cfg.Decks, shoesPerThread, bankroll, cfg.RoundsPerHour)
 		}(i)
 	}
 	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			log.Fatalf("failed playing the shoes: %s", err)
+		}
+	}
+	return overallResults
+}
+
+// dealtRoundsPerHour takes the time lost to hand shuffles out of the configured
+// rounds per hour. Hourly figures count every round at the table, including the
+// ones watched while wonging
+func dealtRoundsPerHour(cfg BJConfig, results blackjack.GameResults) float32 {
+	rounds := float32(results.Rounds())
+	return cfg.RoundsPerHour * rounds / (rounds + float32(results.Shuffles)*cfg.ShuffleRounds)
+}
+
+func Run(cfg BJConfig) {
+	start := time.Now()
+	log.Printf("simming %d shoes of %s w/ %f pen", cfg.ShoesToSim, cfg.BuildGameDescription(), cfg.Penetration)
+	bjRules := newGameRules(cfg)
+	overallResults := simulate(cfg, bjRules)
 
 	variance := float32(0)
 	hourlyVariance := float32(0)
@@ -91,6 +309,15 @@ func Run(cfg BJConfig) {
 	hourlyVariance /= float32(len(overallResults))
 
 	game := fmt.Sprintf("%d deck ", cfg.Decks)
+	if cfg.Infinite {
+		game = "infinite deck "
+	}
+	if cfg.Deck != "" && cfg.Deck != "standard" {
+		game += fmt.Sprintf("(%s) ", cfg.Deck)
+	}
+	if cfg.Game == "spanish21" {
+		game += "Spanish 21 "
+	}
 	if bjRules.DealerHitsSoft17 {
 		game += "H17 "
 	} else {
@@ -113,17 +340,529 @@ func Run(cfg BJConfig) {
 	log.Println("====================================")
 	log.Printf("   Threads %d, elapsed: %s", threads, time.Since(start).Truncate(time.Millisecond))
 	log.Println("====================================")
-	log.Printf("%s, %f pen, %d hands, %f rph", game, bjRules.Penetration, aggregatedResults.Hands, cfg.RoundsPerHour)
+	rph := dealtRoundsPerHour(cfg, aggregatedResults)
+	hourlyEV := aggregatedResults.EV / float32(aggregatedResults.Rounds()) * rph
+	log.Printf("%s, %f pen, %d hands, %f rph", game, bjRules.Penetration, aggregatedResults.Hands, rph)
+	if bjRules.PenetrationDealt > 0 || bjRules.CutCardStdDev > 0 || bjRules.CutCardExtraRounds > 0 {
+		log.Printf("   Cut card: %f dealt, %f cards sd, %d extra rounds", bjRules.PenetrationDealt, bjRules.CutCardStdDev, bjRules.CutCardExtraRounds)
+	}
+	if bjRules.ContinuousShuffle {
+		log.Printf("   Continuous shuffler: %d cards held out", bjRules.CSMReservoir)
+	}
+	if bjRules.Wonging {
+		log.Printf("   Wonging:            in at TC %d, out below TC %d", bjRules.WongInTC, bjRules.WongOutTC)
+		log.Printf("   Hands played:       %d", aggregatedResults.Hands)
+		log.Printf("   Hands observed:     %d", aggregatedResults.Observed)
+	}
 	log.Printf("   EV (units):         %f units", aggregatedResults.EV)
 	log.Printf("   EV (hand):          %f units", aggregatedResults.EV/float32(aggregatedResults.Hands))
-	log.Printf("   EV (hourly):        %f units", aggregatedResults.EV/float32(aggregatedResults.Hands)*cfg.RoundsPerHour)
+	log.Printf("   EV (hourly):        %f units", hourlyEV)
+	if cfg.UnitSize > 0 {
+		log.Printf("   EV ($):             $%.2f", aggregatedResults.EV*cfg.UnitSize)
+		log.Printf("   EV (hand $):        $%.4f", aggregatedResults.EV/float32(aggregatedResults.Hands)*cfg.UnitSize)
+		log.Printf("   EV (hourly $):      $%.2f", hourlyEV*cfg.UnitSize)
+	}
 	log.Printf("   W/L/P:              %f/%f/%f", winPct, losePct, pushPct)
 	log.Printf("   Blackjacks:         %d, %f%%", aggregatedResults.Blackjacks, bjPct)
+	if bjRules.LateSurrender || bjRules.DoubleRescue {
+		log.Printf("   Surrenders:         %d, %d doubles rescued", aggregatedResults.Surrenders, aggregatedResults.Rescues)
+	}
+	if bjRules.Bonuses != nil {
+		log.Printf("   Bonuses:            %d, %f%%", aggregatedResults.Bonuses,
+			float32(aggregatedResults.Bonuses)/float32(aggregatedResults.Hands)*100)
+	}
 	log.Printf("   1 STD (hand):     +-%f units", variance)
 	log.Printf("   1 STD (hourly):   +-%f units", hourlyVariance)
+	if cfg.UnitSize > 0 {
+		log.Printf("   1 STD (hand $):   +-$%.2f", variance*cfg.UnitSize)
+		log.Printf("   1 STD (hourly $): +-$%.2f", hourlyVariance*cfg.UnitSize)
+	}
+	log.Printf("   Bankroll busts:     %d", aggregatedResults.Ruins)
+	if bjRules.Camouflage != nil {
+		log.Printf("   Camouflaged bets:   %d, %f%%", aggregatedResults.CamouflagedBids,
+			float32(aggregatedResults.CamouflagedBids)/float32(aggregatedResults.Hands)*100)
+	}
+	if aggregatedResults.KeyCards > 0 {
+		log.Printf("   Key cards:          %d, %d bet on, %f%% followed by their ace", aggregatedResults.KeyCards,
+			aggregatedResults.SequencedBids, float32(aggregatedResults.SequencedAces)/float32(aggregatedResults.KeyCards)*100)
+	}
+	if bjRules.BurnCards > 0 {
+		log.Printf("   Burn cards:         %d, visible %t", bjRules.BurnCards, bjRules.BurnVisible)
+	}
+	if bjRules.HoleCard != nil {
+		log.Printf("   Hole cards seen:    %d, %f%%", aggregatedResults.ExposedHoleCards,
+			float32(aggregatedResults.ExposedHoleCards)/float32(aggregatedResults.Rounds())*100)
+	}
+	if bjRules.Errors != nil {
+		log.Printf("   Action errors:      %d, %f per hand", aggregatedResults.ActionErrors,
+			float32(aggregatedResults.ActionErrors)/float32(aggregatedResults.Hands))
+		log.Printf("   Miscounts:          %d, %f per hand", aggregatedResults.Miscounts,
+			float32(aggregatedResults.Miscounts)/float32(aggregatedResults.Hands))
+	}
 	log.Printf("TC Stats --- ")
 	log.Printf("   HighTC (avg)        %f ", aggregatedResults.HighTC/float32(aggregatedResults.Hands))
 	log.Printf("   LowTC  (avg)        %f ", aggregatedResults.LowTC/float32(aggregatedResults.Hands))
 	log.Printf("   AvgTC  (avg)        %f ", aggregatedResults.AvgTC/float32(aggregatedResults.Hands))
+	if cfg.AceSideCount {
+		log.Printf("   Ace adjusted bids   %d, %f%%", aggregatedResults.AceAdjustedBids,
+			float32(aggregatedResults.AceAdjustedBids)/float32(aggregatedResults.Hands)*100)
+	}
+	if cfg.OutcomeHours > 0 {
+		dist := blackjack.Bootstrap(aggregatedResults.HourlyAVs, cfg.OutcomeHours, cfg.Trials, cfg.Seed)
+		log.Printf("Outcomes after %.1f hours --- %d trials from %d simulated hours", cfg.OutcomeHours, dist.Len(), len(aggregatedResults.HourlyAVs))
+		logDistribution(dist, cfg.UnitSize)
+	}
+
+}
+
+// GenerateBetRamp runs a flatbet HiLo calibration sim of the configured game and
+// returns the optimal spread for it in the format accepted by --spread
+func GenerateBetRamp(cfg BJConfig, params strategies.BetRampParams) string {
+	start := time.Now()
+	results := calibrate(cfg)
+	ramp := strategies.OptimalBetRamp(results.TCEstimates(), params)
+	spread := strategies.FormatBidspread(ramp.Spread)
+
+	objective := "kelly"
+	if params.Objective == strategies.RampObjectiveSCORE {
+		objective = "SCORE"
+	}
+	log.Println("====================================")
+	log.Printf("   Threads %d, elapsed: %s", threads, time.Since(start).Truncate(time.Millisecond))
+	log.Println("====================================")
+	log.Printf("%s optimal ramp, $%.0f-$%.0f table, $%.0f units, %f kelly, %d hands", objective,
+		params.TableMin, params.TableMax, params.UnitSize, params.KellyFraction, results.Hands)
+	log.Printf("   Spread:             %s", spread)
+	log.Printf("   EV (100 rounds):    $%.2f", ramp.EVPer100)
+	log.Printf("   1 STD (100 rounds): +-$%.2f", ramp.SDPer100)
+	log.Printf("   DI:                 %f", ramp.DI)
+	return spread
+}
+
+// AnalyzeCount prints the betting correlation, playing efficiency and insurance
+// correlation of the count system in the configured game
+func AnalyzeCount(cfg BJConfig, system strategies.CountSystem) {
+	start := time.Now()
+	log.Printf("analyzing %s in %s", system.Name, cfg.BuildGameDescription())
+	analysis := blackjack.AnalyzeCount(newGameRules(cfg), system, cfg.Decks)
+
+	log.Println("====================================")
+	log.Printf("   elapsed: %s", time.Since(start).Truncate(time.Millisecond))
+	log.Println("====================================")
+	log.Printf("   Betting correlation:   %f", analysis.BettingCorrelation)
+	log.Printf("   Playing efficiency:    %f", analysis.PlayingEfficiency)
+	log.Printf("   Insurance correlation: %f", analysis.InsuranceCorrelation)
+	log.Printf("Play correlations --- ")
+	for _, play := range analysis.Plays {
+		log.Printf("   %-10s          %f", play.Play.Name, play.Correlation)
+	}
+}
+
+// EffectsOfRemoval prints the effect of removing each card on the overall
+// expectation of the configured game, and on each of the plays
+func EffectsOfRemoval(cfg BJConfig, plays []blackjack.Play) {
+	start := time.Now()
+	bjRules := newGameRules(cfg)
+	comp := bjRules.Deck.Composition(cfg.Decks)
+	log.Printf("effects of removal in %s", cfg.BuildGameDescription())
+	eor := bjRules.EffectsOfRemoval(comp)
+
+	log.Println("====================================")
+	log.Printf("   elapsed: %s", time.Since(start).Truncate(time.Millisecond))
+	log.Println("====================================")
+	log.Printf("   %-10s %-8s  %-8s %-8s %-8s %-8s %-8s %-8s %-8s %-8s %-8s %-8s",
+		"", "value", "2", "3", "4", "5", "6", "7", "8", "9", "T", "A")
+	log.Printf("   %-10s %+.4f%%  %s", "EV", bjRules.ExpectedValue(comp)*100, eor.ToString())
+	for _, play := range plays {
+		log.Printf("   %-10s %+.4f%%  %s", play.Name, bjRules.PlayValue(play, comp)*100,
+			bjRules.PlayEffectsOfRemoval(play, comp).ToString())
+	}
+}
+
+// GenerateIndices sims the plays under the configured tracking strategy and
+// returns the index table found, saving it to `out` when set
+func GenerateIndices(cfg BJConfig, plays []blackjack.Play, out string) []blackjack.Index {
+	if cfg.Infinite {
+		// the count never moves off an infinite deck, so there's nothing to index
+		log.Fatalf("indices are played out of the shoe's unseen cards, --infinite can't be used")
+	}
+	start := time.Now()
+	log.Printf("simming %d plays over %d shoes of %s w/ %f pen", len(plays), cfg.ShoesToSim, cfg.BuildGameDescription(), cfg.Penetration)
+	// indices are found off basic strategy
+	cfg.Indices = nil
+	bjRules := newGameRules(cfg)
+
+	threadGains := make([][]map[int]blackjack.TCResult, threads)
+	errs := make([]error, threads)
+	wg := sync.WaitGroup{}
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			rules := *bjRules
+			if rules.Seed != 0 {
+				rules.Seed += uint64(idx)
+			}
+			threadGains[idx], errs[idx] = blackjack.SimulatePlays(rules, cfg.Decks, cfg.ShoesToSim/threads, plays)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			log.Fatalf("failed simming the plays: %s", err)
+		}
+	}
+
+	log.Println("====================================")
+	log.Printf("   Threads %d, elapsed: %s", threads, time.Since(start).Truncate(time.Millisecond))
+	log.Println("====================================")
+	indices := make([]blackjack.Index, 0, len(plays))
+	for p, play := range plays {
+		gains := map[int]blackjack.TCResult{}
+		for _, g := range threadGains {
+			for tc, res := range g[p] {
+				agg := gains[tc]
+				agg.Rounds += res.Rounds
+				agg.EV += res.EV
+				agg.EVSquared += res.EVSquared
+				gains[tc] = agg
+			}
+		}
+		index, found := blackjack.FindIndex(play, gains)
+		if !found {
+			log.Printf("   %-10s          no crossover found", play.Name)
+			continue
+		}
+		action := play.Action.ToString()
+		if play.Insurance {
+			action = "insure"
+		}
+		log.Printf("   %-10s          %s, %s", play.Name, action, index.ToString())
+		indices = append(indices, index)
+	}
+	if out != "" {
+		if err := blackjack.SaveIndexTable(out, indices); err != nil {
+			log.Fatalf("failed saving index table: %s", err)
+		}
+		log.Printf("saved index table to %s", out)
+	}
+	return indices
+}
+
+// namedRun is one variation of the game in a cost comparison
+type namedRun struct {
+	name string
+	cfg  BJConfig
+}
+
+// compareRuns sims each run off the same seed and reports what each costs
+// against the first. Every run plays the same shoes so the differences come
+// down to the variation rather than the cards
+func compareRuns(runs []namedRun) {
+	start := time.Now()
+	evs := make([]float32, len(runs))
+	for i, run := range runs {
+		results := blackjack.AggregateResults(simulate(run.cfg, newGameRules(run.cfg))...)
+		evs[i] = results.EV / float32(results.Hands)
+	}
+
+	rph := runs[0].cfg.RoundsPerHour
+	log.Println("====================================")
+	log.Printf("   Threads %d, elapsed: %s", threads, time.Since(start).Truncate(time.Millisecond))
+	log.Println("====================================")
+	for i, run := range runs {
+		cost := evs[0] - evs[i]
+		log.Printf("   %-14s      EV %f units/hand, %f units/hour, costs %f units/hour", run.name,
+			evs[i], evs[i]*rph, cost*rph)
+	}
+}
+
+// ErrorCosts sims the configured game with perfect play, with each type of
+// player error on its own and with all of them
+func ErrorCosts(cfg BJConfig) {
+	if cfg.Seed == 0 {
+		cfg.Seed = rand.Uint64()
+	}
+	log.Printf("attributing player errors over %d shoes of %s, seed %d", cfg.ShoesToSim, cfg.BuildGameDescription(), cfg.Seed)
+
+	runs := []namedRun{}
+	addRun := func(name string, actionErrors, miscounts, tcNoise float64) {
+		run := cfg
+		run.ActionErrors, run.Miscounts, run.TCNoise = actionErrors, miscounts, tcNoise
+		runs = append(runs, namedRun{name: name, cfg: run})
+	}
+	addRun("perfect", 0, 0, 0)
+	if cfg.ActionErrors > 0 {
+		addRun("action errors", cfg.ActionErrors, 0, 0)
+	}
+	if cfg.Miscounts > 0 {
+		addRun("miscounts", 0, cfg.Miscounts, 0)
+	}
+	if cfg.TCNoise > 0 {
+		addRun("tc noise", 0, 0, cfg.TCNoise)
+	}
+	addRun("all errors", cfg.ActionErrors, cfg.Miscounts, cfg.TCNoise)
+	compareRuns(runs)
+}
+
+// CamouflageCosts sims the configured game betting the raw spread, with each
+// camouflage rule on its own and with all of them
+func CamouflageCosts(cfg BJConfig) {
+	if cfg.Seed == 0 {
+		cfg.Seed = rand.Uint64()
+	}
+	log.Printf("costing camouflage over %d shoes of %s, seed %d", cfg.ShoesToSim, cfg.BuildGameDescription(), cfg.Seed)
+
+	runs := []namedRun{}
+	addRun := func(name string, camouflage blackjack.Camouflage) {
+		run := cfg
+		run.Camouflage = camouflage
+		runs = append(runs, namedRun{name: name, cfg: run})
+	}
+	all := cfg.Camouflage
+	addRun("raw spread", blackjack.Camouflage{})
+	if all.MaxIncreaseRatio > 0 {
+		addRun("max raise", blackjack.Camouflage{MaxIncreaseRatio: all.MaxIncreaseRatio})
+	}
+	if all.ParlayOnly {
+		addRun("parlay only", blackjack.Camouflage{ParlayOnly: true})
+	}
+	if all.MaxDecreaseRatio > 0 {
+		addRun("max drop", blackjack.Camouflage{MaxDecreaseRatio: all.MaxDecreaseRatio})
+	}
+	if all.OffCountRate > 0 {
+		addRun("off count", blackjack.Camouflage{OffCountRate: all.OffCountRate})
+	}
+	addRun("all camouflage", all)
+	compareRuns(runs)
+}
+
+// AceSideCosts sims the configured HiLo game with and without the ace side
+// count, off the same shoes, and reports what the side count is worth
+func AceSideCosts(cfg BJConfig) {
+	if cfg.Strategy != "hilo" {
+		log.Fatalf("the ace side count is kept alongside hilo, not %s", cfg.Strategy)
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = rand.Uint64()
+	}
+	log.Printf("comparing the ace side count over %d shoes of %s, seed %d", cfg.ShoesToSim, cfg.BuildGameDescription(), cfg.Seed)
+
+	without, with := cfg, cfg
+	without.AceSideCount, with.AceSideCount = false, true
+	compareRuns([]namedRun{{name: "hilo", cfg: without}, {name: "ace side count", cfg: with}})
+}
+
+// RunTeam sims a big player team across the configured tables and reports the
+// team's combined results
+func RunTeam(cfg BJConfig, params blackjack.TeamParams) {
+	start := time.Now()
+	log.Printf("simming a %d table team over %d shoes of %s w/ %f pen", params.Tables, cfg.ShoesToSim, cfg.BuildGameDescription(), cfg.Penetration)
+	bjRules := newGameRules(cfg)
+
+	threadResults := make([]blackjack.TeamResults, threads)
+	errs := make([]error, threads)
+	wg := sync.WaitGroup{}
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			rules := *bjRules
+			if rules.Seed != 0 {
+				rules.Seed += uint64(idx)
+			}
+			threadResults[idx], errs[idx] = blackjack.PlayTeam(rules, cfg.Decks, cfg.ShoesToSim/threads, params, cfg.RoundsPerHour)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			log.Fatalf("failed playing the team: %s", err)
+		}
+	}
+	results := blackjack.AggregateTeamResults(threadResults...)
+	hourlyEV, hourlySD := results.Hourly()
+
+	log.Println("====================================")
+	log.Printf("   Threads %d, elapsed: %s", threads, time.Since(start).Truncate(time.Millisecond))
+	log.Println("====================================")
+	log.Printf("%d tables, call in at TC %d, leave below TC %d, %d rounds travel", params.Tables,
+		params.CallInTC, params.LeaveTC, params.TravelRounds)
+	log.Printf("   Hours:              %d", len(results.HourlyAVs))
+	log.Printf("   Call ins:           %d, %f per hour", results.CallIns, float32(results.CallIns)/float32(len(results.HourlyAVs)))
+	log.Printf("   Spotter hands:      %d", results.SpotterHands)
+	log.Printf("   Big player hands:   %d", results.BigPlayerHands)
+	log.Printf("   Spotter EV:         %f units", results.SpotterEV)
+	log.Printf("   Big player EV:      %f units, %f units/hand", results.BigPlayerEV,
+		results.BigPlayerEV/float32(results.BigPlayerHands))
+	log.Printf("   Team EV:            %f units", results.EV())
+	log.Printf("   Team EV (hourly):   %f units", hourlyEV)
+	log.Printf("   1 STD (hourly):   +-%f units", hourlySD)
+	if cfg.UnitSize > 0 {
+		log.Printf("   Team EV (hourly $): $%.2f", hourlyEV*cfg.UnitSize)
+		log.Printf("   1 STD (hourly $): +-$%.2f", hourlySD*cfg.UnitSize)
+	}
+}
+
+var reportPercentiles = []float64{1, 5, 25, 50, 75, 95, 99}
 
+const histogramBins = 20
+const histogramWidth = 50
+
+// logDistribution prints the percentiles and an ascii histogram of the outcomes
+func logDistribution(dist blackjack.Distribution, unitSize float32) {
+	log.Printf("   Mean:               %f units", dist.Mean())
+	log.Printf("   1 STD:            +-%f units", dist.StdDev())
+	log.Printf("   P(behind):          %f%%", dist.ProbabilityBelow(0)*100)
+	for _, p := range reportPercentiles {
+		if unitSize > 0 {
+			log.Printf("   %2.0f%%:                %f units, $%.2f", p, dist.Percentile(p), dist.Percentile(p)*float64(unitSize))
+		} else {
+			log.Printf("   %2.0f%%:                %f units", p, dist.Percentile(p))
+		}
+	}
+
+	histogram := dist.Histogram(histogramBins)
+	largest := 0
+	for _, bin := range histogram {
+		if bin.Count > largest {
+			largest = bin.Count
+		}
+	}
+	log.Printf("Histogram (units) --- ")
+	for _, bin := range histogram {
+		bar := strings.Repeat("#", bin.Count*histogramWidth/largest)
+		log.Printf("   %10.1f to %10.1f | %-*s %d", bin.Low, bin.High, histogramWidth, bar, bin.Count)
+	}
+}
+
+// RunSessions sims sessions of the configured game and reports the distribution
+// of their results
+func RunSessions(cfg BJConfig, sessions int, params blackjack.SessionParams) {
+	start := time.Now()
+	log.Printf("simming %d %f hour sessions of %s w/ %f pen", sessions, params.Hours, cfg.BuildGameDescription(), cfg.Penetration)
+	bjRules := newGameRules(cfg)
+	bankroll := cfg.Bankroll
+	if bankroll <= 0 {
+		bankroll = defaultBankroll
+	}
+
+	threadResults := make([]blackjack.SessionResults, threads)
+	errs := make([]error, threads)
+	wg := sync.WaitGroup{}
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			rules := *bjRules
+			if rules.Seed != 0 {
+				rules.Seed += uint64(idx)
+			}
+			threadResults[idx], errs[idx] = blackjack.PlaySessions(rules, cfg.Decks, sessions/threads, params, cfg.RoundsPerHour, bankroll)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			log.Fatalf("failed playing the sessions: %s", err)
+		}
+	}
+	results := blackjack.AggregateSessionResults(threadResults...)
+	dist := blackjack.NewDistribution(results.Results)
+
+	log.Println("====================================")
+	log.Printf("   Threads %d, elapsed: %s", threads, time.Since(start).Truncate(time.Millisecond))
+	log.Println("====================================")
+	log.Printf("%d sessions, %f hours, stop loss %f units, win goal %f units", dist.Len(), params.Hours, params.StopLoss, params.WinGoal)
+	log.Printf("   Hours (avg):        %f", float32(results.Rounds)/cfg.RoundsPerHour/float32(dist.Len()))
+	log.Printf("   Stop losses:        %d, %f%%", results.StopLosses, float32(results.StopLosses)/float32(dist.Len())*100)
+	log.Printf("   Win goals:          %d, %f%%", results.WinGoals, float32(results.WinGoals)/float32(dist.Len())*100)
+	logDistribution(dist, cfg.UnitSize)
+}
+
+// CompareCSM sims the configured game dealt from a shoe and from a continuous
+// shuffler, the same bets, rules and seed either way
+func CompareCSM(cfg BJConfig) {
+	if cfg.Seed == 0 {
+		cfg.Seed = rand.Uint64()
+	}
+	start := time.Now()
+	log.Printf("comparing shoe and CSM over %d shoes of %s, seed %d", cfg.ShoesToSim, cfg.BuildGameDescription(), cfg.Seed)
+	games := []namedRun{}
+	shoe := cfg
+	shoe.CSM = false
+	games = append(games, namedRun{name: "shoe", cfg: shoe})
+	csm := cfg
+	csm.CSM = true
+	games = append(games, namedRun{name: "csm", cfg: csm})
+
+	results := make([]blackjack.GameResults, len(games))
+	for i, game := range games {
+		results[i] = blackjack.AggregateResults(simulate(game.cfg, newGameRules(game.cfg))...)
+	}
+
+	log.Println("====================================")
+	log.Printf("   Threads %d, elapsed: %s", threads, time.Since(start).Truncate(time.Millisecond))
+	log.Println("====================================")
+	log.Printf("%d cards held out by the CSM, hand shuffles take %f rounds", cfg.CSMReservoir, cfg.ShuffleRounds)
+	for i, game := range games {
+		r := results[i]
+		rph := dealtRoundsPerHour(game.cfg, r)
+		log.Printf("   %-5s EV %f units/hand, %f rounds/hour, %f units/hour, %d shuffles", game.name,
+			r.EV/float32(r.Hands), rph, r.EV/float32(r.Rounds())*rph, r.Shuffles)
+	}
+}
+
+// CompareTracking sims the configured game's shuffle counted with HiLo and
+// shuffle tracked, off the same shoes and bet spread
+func CompareTracking(cfg BJConfig) {
+	if cfg.Seed == 0 {
+		cfg.Seed = rand.Uint64()
+	}
+	if cfg.Shuffle == "" {
+		log.Println("a random shuffle can't be tracked, set --shuffle to the casino's procedure")
+	}
+	log.Printf("comparing counting and tracking over %d shoes of %s, seed %d", cfg.ShoesToSim, cfg.BuildGameDescription(), cfg.Seed)
+	counting := cfg
+	counting.Strategy = "hilo"
+	tracking := cfg
+	tracking.Strategy = "track"
+	// a negative cost is what tracking gains over counting
+	compareRuns([]namedRun{{name: "counting", cfg: counting}, {name: "tracking", cfg: tracking}})
+}
+
+// CompareSequencing sims ace sequencing against counting for each shuffle, off
+// the same shoes, to show how the edge falls away as the shuffle improves
+func CompareSequencing(cfg BJConfig, shuffles []string) {
+	start := time.Now()
+	if cfg.Seed == 0 {
+		cfg.Seed = rand.Uint64()
+	}
+	log.Printf("comparing ace sequencing and counting over %d shoes of %s, seed %d", cfg.ShoesToSim, cfg.BuildGameDescription(), cfg.Seed)
+	type sequenced struct {
+		counting   blackjack.GameResults
+		sequencing blackjack.GameResults
+	}
+	results := make([]sequenced, len(shuffles))
+	for i, shuffle := range shuffles {
+		counting := cfg
+		counting.Shuffle, counting.Strategy = shuffle, "hilo"
+		sequencing := cfg
+		sequencing.Shuffle, sequencing.Strategy = shuffle, "sequence"
+		results[i].counting = blackjack.AggregateResults(simulate(counting, newGameRules(counting))...)
+		results[i].sequencing = blackjack.AggregateResults(simulate(sequencing, newGameRules(sequencing))...)
+	}
+
+	log.Println("====================================")
+	log.Printf("   Threads %d, elapsed: %s", threads, time.Since(start).Truncate(time.Millisecond))
+	log.Println("====================================")
+	for i, shuffle := range shuffles {
+		c, s := results[i].counting, results[i].sequencing
+		hitRate := float32(0)
+		if s.KeyCards > 0 {
+			hitRate = float32(s.SequencedAces) / float32(s.KeyCards) * 100
+		}
+		log.Printf("   %-30s counting %f units/round, sequencing %f units/round, %f%% of key cards followed by their ace",
+			shuffle, c.EV/float32(c.Rounds()), s.EV/float32(s.Rounds()), hitRate)
+	}
 }
@@ -2,4 +2,4 @@ module github.com/onemorebsmith/blackjack-solver
 
 go 1.17
 
-require github.com/alecthomas/kong v1.9.0 // indirect
+require github.com/alecthomas/kong v1.9.0
@@ -41,6 +41,26 @@ var cards = []Card{
 	{Name: "A", Value: 11},
 }
 
+// Rank is the card's index in a suit, 2 through A, -1 for a card without a name
+func (c Card) Rank() int {
+	switch c.Name {
+	case "10":
+		return 8
+	case "J":
+		return 9
+	case "Q":
+		return 10
+	case "K":
+		return 11
+	case "A":
+		return 12
+	}
+	if len(c.Name) == 1 && c.Name[0] >= '2' && c.Name[0] <= '9' {
+		return int(c.Name[0] - '2')
+	}
+	return -1
+}
+
 func SuitToString(s Suit) string {
 	switch s {
 	case SuitClubs:
@@ -0,0 +1,36 @@
+package core
+
+// Composition counts cards by blackjack value, indexed by value with aces at
+// 11 so 0 and 1 are always empty
+type Composition [12]int
+
+// Ten valued cards per suit, 10 J Q K
+const tensPerSuit = 4
+
+func NewComposition(decks int) Composition {
+	comp := Composition{}
+	for value := 2; value <= 11; value++ {
+		comp[value] = Suits * decks
+	}
+	comp[10] = tensPerSuit * Suits * decks
+	return comp
+}
+
+func (c Composition) Total() int {
+	total := 0
+	for _, v := range c {
+		total += v
+	}
+	return total
+}
+
+// Remove returns a copy of the composition with one card of the value taken out
+func (c Composition) Remove(value int) Composition {
+	c[value]--
+	return c
+}
+
+// Probability of the next card being the value
+func (c Composition) Probability(value int) float64 {
+	return float64(c[value]) / float64(c.Total())
+}
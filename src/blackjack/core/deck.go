@@ -1,6 +1,7 @@
 package core
 
 import (
+	"math"
 	"math/rand/v2"
 	"strings"
 )
@@ -13,38 +14,124 @@ type Deck struct {
 	Cards       []Card
 	idx         int
 	deckSize    int
+	spec        DeckComposition // the cards in each of the decks
 	PreviewCard func(c Card)
 	source      *rand.Rand
+
+	// continuous shuffling machine, discards go back in behind the reservoir of cards held out
+	continuous bool
+	reservoir  int
+	dealt      int
+
+	cutCard        CutCard
+	cutSet         bool
+	cutPosition    int // cards in front of the cut card
+	roundsAfterCut int // rounds finished since the cut card came out
+
+	// cards burned off the top after each shuffle, hidden burns are never seen by the players
+	burn        int
+	burnVisible bool
+	burnPending bool
+	hiddenBurns int // hidden burn cards at the front of the shoe
+
+	shuffler Shuffler // nil for a perfect shuffle
+	fallback bool     // deal random cards once the deck runs out
+
+	// the cards the players haven't seen, by value and by suit and rank
+	unseen      Composition
+	unseenRanks [SuiteLast][SuiteSize]int
+	faceDown    []Card // dealt face down and not turned over yet, still unseen
+}
+
+// CutCard describes where the dealer places the cut card after each shuffle
+type CutCard struct {
+	Penetration  float32 // decks cut off, or the share of the shoe dealt when PercentDealt
+	PercentDealt bool
+	StdDev       float32 // spread of the placement, in cards
+	ExtraRounds  int     // rounds dealt after the one the cut card comes out in
 }
 
 // Creates `shoe` of 1+ decks, unshuffled initially
 func GenerateShoe(decks int) *Deck {
-	shoe := GenerateDeck()
+	return GenerateShoeOf(decks, StandardDeck)
+}
+
+// Creates `shoe` of 1+ decks of the composition, unshuffled initially
+func GenerateShoeOf(decks int, spec DeckComposition) *Deck {
+	shoe := GenerateDeckOf(spec)
 	for i := 1; i < decks; i++ {
-		additionalDeck := GenerateDeck()
+		additionalDeck := GenerateDeckOf(spec)
 		shoe.Cards = append(shoe.Cards, additionalDeck.Cards...)
 	}
-	shoe.source = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
-	shoe.deckSize = decks * DeckSize
+	shoe.deckSize = decks * shoe.spec.Size()
+	shoe.resetUnseen()
 	return shoe
 }
 
 // Creates a non-suffled full 52 card deck
 func GenerateDeck() *Deck {
-	all := make([]Card, 0, DeckSize)
+	return GenerateDeckOf(StandardDeck)
+}
+
+// Creates a non-suffled deck of the composition
+func GenerateDeckOf(spec DeckComposition) *Deck {
+	all := make([]Card, 0, spec.Size())
 	for suiteIdx := SuiteFirst; suiteIdx < SuiteLast; suiteIdx++ {
 		for cardIdx := 0; cardIdx < SuiteSize; cardIdx++ {
-			card := cards[cardIdx]
-			card.Suit = Suit(suiteIdx)
-			all = append(all, card)
+			for n := 0; n < spec[cardIdx]; n++ {
+				card := cards[cardIdx]
+				card.Suit = Suit(suiteIdx)
+				all = append(all, card)
+			}
 		}
 	}
 
-	return &Deck{
+	deck := &Deck{
 		idx:      0,
-		deckSize: DeckSize,
+		deckSize: len(all),
+		spec:     spec,
 		Cards:    all,
+		source:   rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+	deck.resetUnseen()
+	return deck
+}
+
+// resetUnseen counts every card in the shoe as unseen
+func (d *Deck) resetUnseen() {
+	d.unseen = Composition{}
+	d.unseenRanks = [SuiteLast][SuiteSize]int{}
+	d.faceDown = nil
+	for _, c := range d.Cards[:d.deckSize] {
+		d.see(c, 1)
+	}
+}
+
+func (d *Deck) see(c Card, delta int) {
+	d.unseen[c.Value] += delta
+	if rank := c.Rank(); rank >= 0 && c.Suit < SuiteLast {
+		d.unseenRanks[c.Suit][rank] += delta
+	}
+}
+
+// Composition is how many of each value the players haven't seen, which takes
+// in any hidden burn cards
+func (d *Deck) Composition() Composition {
+	return d.unseen
+}
+
+// UnseenOf is how many cards of the value the players haven't seen
+func (d *Deck) UnseenOf(value int) int {
+	return d.unseen[value]
+}
+
+// UnseenCard is how many copies of the card, by rank and suit, the players haven't seen
+func (d *Deck) UnseenCard(c Card) int {
+	rank := c.Rank()
+	if rank < 0 || c.Suit >= SuiteLast {
+		return 0
 	}
+	return d.unseenRanks[c.Suit][rank]
 }
 
 func (d *Deck) ToString() string {
@@ -57,20 +144,140 @@ func (d *Deck) ToString() string {
 }
 
 func (d *Deck) Shuffle() *Deck {
-	// swiped & modified from https://go.dev/src/math/rand/v2/rand.go
-	n := d.deckSize - 1
-	for i := n - 1; i > 0; i-- {
-		j := int(d.source.Uint64N(uint64(i + 1)))
-		d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
+	if d.shuffler != nil {
+		d.shuffleWith(d.shuffler)
+	} else {
+		// swiped & modified from https://go.dev/src/math/rand/v2/rand.go
+		n := d.deckSize - 1
+		for i := n - 1; i > 0; i-- {
+			j := int(d.source.Uint64N(uint64(i + 1)))
+			d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
+		}
 	}
 
 	d.idx = 0
+	d.dealt = 0
+	d.hiddenBurns = 0
+	d.burnPending = d.burn > 0
+	d.resetUnseen()
+	d.placeCutCard()
+	return d
+}
+
+// SetShuffler shuffles the shoe the way a dealer would. The cards are picked up
+// in the order they were dealt, the unplayed cards last
+func (d *Deck) SetShuffler(shuffler Shuffler) *Deck {
+	d.shuffler = shuffler
+	return d
+}
+
+func (d *Deck) shuffleWith(shuffler Shuffler) {
+	order := make([]int, d.deckSize)
+	for i := range order {
+		order[i] = i
+	}
+	shuffler.Shuffle(order, d.source)
+	shuffled := make([]Card, d.deckSize)
+	for i, from := range order {
+		shuffled[i] = d.Cards[from]
+	}
+	copy(d.Cards, shuffled)
+}
+
+// SetBurn burns `cards` off the top of the shoe after every shuffle, visible
+// burns are shown to the table and go through the preview
+func (d *Deck) SetBurn(cards int, visible bool) *Deck {
+	d.burn = cards
+	d.burnVisible = visible
 	return d
 }
 
+// Burn burns the cards due after a shuffle, if they haven't been already. It's
+// done before the first card is dealt at the latest
+func (d *Deck) Burn() {
+	if !d.burnPending {
+		return
+	}
+	d.burnPending = false
+	for i := 0; i < d.burn && d.idx < d.deckSize; i++ {
+		if d.burnVisible {
+			d.Deal()
+			continue
+		}
+		d.idx++
+		d.dealt++
+		d.hiddenBurns++
+	}
+}
+
+func (d *Deck) SetCutCard(cut CutCard) *Deck {
+	d.cutCard = cut
+	d.cutSet = true
+	d.placeCutCard()
+	return d
+}
+
+// HasCutCard is true once SetCutCard has been called, without it the shoe is
+// dealt to the last card
+func (d *Deck) HasCutCard() bool {
+	return d.cutSet
+}
+
+func (d *Deck) placeCutCard() {
+	d.roundsAfterCut = 0
+	position := float64(d.deckSize) - float64(d.cutCard.Penetration)*float64(d.spec.Size())
+	if d.cutCard.PercentDealt {
+		position = float64(d.deckSize) * float64(d.cutCard.Penetration)
+	}
+	if d.cutCard.StdDev > 0 {
+		position += d.source.NormFloat64() * float64(d.cutCard.StdDev)
+	}
+	d.cutPosition = int(math.Round(position))
+	if d.cutPosition < 1 {
+		d.cutPosition = 1
+	} else if d.cutPosition > d.deckSize {
+		d.cutPosition = d.deckSize
+	}
+}
+
+// CutCardOut is true once the cut card has come out of the shoe
+func (d *Deck) CutCardOut() bool {
+	return d.dealt > d.cutPosition
+}
+
+// EndRound is called after every round, it returns the discards to a continuous
+// shuffler and keeps track of the rounds dealt after the cut card
+func (d *Deck) EndRound() {
+	if d.CutCardOut() {
+		d.roundsAfterCut++
+	}
+	d.ReturnDiscards()
+}
+
+// ShoeFinished is true once the round the cut card came out in, and any extra
+// rounds after it, have been dealt
+func (d *Deck) ShoeFinished() bool {
+	return d.CutCardOut() && d.roundsAfterCut > d.cutCard.ExtraRounds
+}
+
+// Deal deals the next card, it panics with ErrOutOfCards once the deck is empty
 func (d *Deck) Deal() Card {
+	c, err := d.Next()
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func (d *Deck) deal(faceUp bool) Card {
 	c := d.Cards[d.idx]
 	d.idx++
+	d.dealt++
+	if !faceUp {
+		d.faceDown = append(d.faceDown, c)
+		return c
+	}
+	d.see(c, -1)
 	if d.PreviewCard != nil {
 		d.PreviewCard(c)
 	}
@@ -82,6 +289,110 @@ func (d *Deck) Remaining() int {
 	return d.deckSize - d.idx
 }
 
+// Decks is the number of decks the shoe was built from
+func (d *Deck) Decks() float32 {
+	return float32(d.deckSize) / float32(d.spec.Size())
+}
+
 func (d *Deck) EstimateRemaining() float32 {
-	return float32((d.deckSize - d.idx)) / 52.0
+	return float32((d.deckSize - d.idx)) / float32(d.spec.Size())
+}
+
+// Spec is the composition of each of the decks in the shoe
+func (d *Deck) Spec() DeckComposition {
+	return d.spec
+}
+
+// Unseen returns a shuffled copy of the cards not yet dealt plus any hidden
+// burns, without the preview, for playing out hypothetical rounds from the current shoe
+func (d *Deck) Unseen() *Deck {
+	cards := make([]Card, 0, d.deckSize-d.idx+d.hiddenBurns)
+	cards = append(cards, d.Cards[:d.hiddenBurns]...)
+	cards = append(cards, d.Cards[d.idx:d.deckSize]...)
+	unseen := &Deck{
+		Cards:    cards,
+		deckSize: len(cards),
+		spec:     d.spec,
+		source:   rand.New(rand.NewPCG(d.source.Uint64(), d.source.Uint64())),
+	}
+	unseen.resetUnseen()
+	unseen.source.Shuffle(len(cards), func(i, j int) {
+		cards[i], cards[j] = cards[j], cards[i]
+	})
+	return unseen
+}
+
+// Clone copies the deck and its position so the same cards can be dealt twice
+func (d *Deck) Clone() *Deck {
+	cloned := *d
+	cloned.Cards = make([]Card, len(d.Cards))
+	copy(cloned.Cards, d.Cards)
+	cloned.faceDown = append([]Card(nil), d.faceDown...)
+	return &cloned
+}
+
+// Take deals a random card of the value out of order, false if none are left.
+// Picking the first one would leave the cards ahead of it short of the value
+func (d *Deck) Take(value int) (Card, bool) {
+	found := -1
+	matches := 0
+	for i := d.idx; i < d.deckSize; i++ {
+		if d.Cards[i].Value == value {
+			matches++
+			if d.source.IntN(matches) == 0 {
+				found = i
+			}
+		}
+	}
+	if found < 0 {
+		return Card{}, false
+	}
+	d.Cards[found], d.Cards[d.idx] = d.Cards[d.idx], d.Cards[found]
+	return d.Deal(), true
+}
+
+// Seed reseeds the shuffle so runs can be repeated
+func (d *Deck) Seed(seed uint64) *Deck {
+	d.source = rand.New(rand.NewPCG(seed, seed))
+	return d
+}
+
+// SetContinuousShuffle makes the deck a continuous shuffling machine that holds
+// `reservoir` cards out ready to deal, discards are mixed back in behind them
+func (d *Deck) SetContinuousShuffle(reservoir int) *Deck {
+	d.continuous = true
+	d.reservoir = reservoir
+	return d
+}
+
+func (d *Deck) ContinuousShuffle() bool {
+	return d.continuous
+}
+
+// Dealt is the number of cards dealt since the last shuffle, including any returned
+func (d *Deck) Dealt() int {
+	return d.dealt
+}
+
+// ReturnDiscards puts the dealt cards back into a continuous shuffler. The held
+// out cards come next in the same order, the discards are mixed in with the rest
+func (d *Deck) ReturnDiscards() {
+	if !d.continuous || d.idx == 0 {
+		return
+	}
+	held := d.reservoir
+	if held > d.deckSize-d.idx {
+		held = d.deckSize - d.idx
+	}
+	mixed := make([]Card, 0, d.deckSize-held)
+	mixed = append(mixed, d.Cards[:d.idx]...)
+	mixed = append(mixed, d.Cards[d.idx+held:d.deckSize]...)
+	d.source.Shuffle(len(mixed), func(i, j int) {
+		mixed[i], mixed[j] = mixed[j], mixed[i]
+	})
+	copy(d.Cards, d.Cards[d.idx:d.idx+held])
+	copy(d.Cards[held:], mixed)
+	d.idx = 0
+	d.hiddenBurns = 0
+	d.resetUnseen()
 }
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// DeckComposition is how many of each rank, 2 through A, go into every suit of
+// a deck
+type DeckComposition [SuiteSize]int
+
+var StandardDeck = DeckComposition{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+
+// SpanishDeck has the 10 spot cards taken out, leaving the faces
+var SpanishDeck = StandardDeck.Without("10")
+
+// Size is the number of cards in a deck
+func (dc DeckComposition) Size() int {
+	size := 0
+	for _, n := range dc {
+		size += n * Suits
+	}
+	return size
+}
+
+// Without takes every card of the rank out of the deck
+func (dc DeckComposition) Without(rank string) DeckComposition {
+	return dc.With(rank, 0)
+}
+
+// With puts n cards of the rank in each suit
+func (dc DeckComposition) With(rank string, n int) DeckComposition {
+	if idx := (Card{Name: rank}).Rank(); idx >= 0 {
+		dc[idx] = n
+	}
+	return dc
+}
+
+// Composition counts the cards of each value in a shoe of the decks
+func (dc DeckComposition) Composition(decks int) Composition {
+	comp := Composition{}
+	for rank, n := range dc {
+		comp[cards[rank].Value] += n * Suits * decks
+	}
+	return comp
+}
+
+// draw picks a card at random from a full deck of the composition
+func (dc DeckComposition) draw(source *rand.Rand) Card {
+	drawn := source.IntN(dc.Size())
+	for rank, n := range dc {
+		if drawn < n*Suits {
+			c := cards[rank]
+			c.Suit = Suit(int(SuiteFirst) + drawn/n)
+			return c
+		}
+		drawn -= n * Suits
+	}
+	return Card{}
+}
+
+func (dc DeckComposition) ToString() string {
+	switch dc {
+	case StandardDeck:
+		return "standard"
+	case SpanishDeck:
+		return "spanish"
+	}
+	changes := []string{}
+	for rank, n := range dc {
+		if n != 1 {
+			changes = append(changes, fmt.Sprintf("%s:%d", cards[rank].Name, n))
+		}
+	}
+	return strings.Join(changes, ",")
+}
+
+// ParseDeckComposition reads a deck from a preset, standard or spanish, or a
+// list of changes to a standard deck. -R takes the rank out and R:n puts n of
+// it in each suit, e.g. "-10,A:2" is a Spanish deck with double the aces
+func ParseDeckComposition(spec string) (DeckComposition, error) {
+	switch strings.ToLower(strings.TrimSpace(spec)) {
+	case "", "standard":
+		return StandardDeck, nil
+	case "spanish":
+		return SpanishDeck, nil
+	}
+	dc := StandardDeck
+	for _, change := range strings.Split(spec, ",") {
+		change = strings.ToUpper(strings.TrimSpace(change))
+		rank, n := change, 1
+		if strings.HasPrefix(change, "-") {
+			rank, n = change[1:], 0
+		} else if i := strings.Index(change, ":"); i >= 0 {
+			count, err := strconv.Atoi(change[i+1:])
+			if err != nil || count < 0 {
+				return dc, fmt.Errorf("invalid count in '%s'", change)
+			}
+			rank, n = change[:i], count
+		} else {
+			return dc, fmt.Errorf("invalid deck change '%s'", change)
+		}
+		if rank == "T" {
+			rank = "10"
+		}
+		if (Card{Name: rank}).Rank() < 0 {
+			return dc, fmt.Errorf("invalid rank in '%s'", change)
+		}
+		dc = dc.With(rank, n)
+	}
+	if dc == (DeckComposition{}) {
+		return dc, fmt.Errorf("'%s' leaves no cards in the deck", spec)
+	}
+	return dc, nil
+}
@@ -0,0 +1,66 @@
+package core
+
+import "testing"
+
+func TestParseDeckComposition(t *testing.T) {
+	tests := []struct {
+		Spec     string
+		Expected DeckComposition
+		Size     int
+	}{
+		{Spec: "", Expected: StandardDeck, Size: 52},
+		{Spec: "spanish", Expected: SpanishDeck, Size: 48},
+		{Spec: "-10", Expected: SpanishDeck, Size: 48},
+		{Spec: "-T, A:2", Expected: SpanishDeck.With("A", 2), Size: 52},
+		{Spec: "-5,-6", Expected: StandardDeck.Without("5").Without("6"), Size: 44},
+	}
+	for _, test := range tests {
+		dc, err := ParseDeckComposition(test.Spec)
+		if err != nil {
+			t.Fatalf("'%s' failed to parse: %s", test.Spec, err)
+		}
+		if dc != test.Expected || dc.Size() != test.Size {
+			t.Fatalf("'%s' parsed to %s of %d cards", test.Spec, dc.ToString(), dc.Size())
+		}
+	}
+	for _, spec := range []string{"10", "-X", "A:-1", "A:two", "-2,-3,-4,-5,-6,-7,-8,-9,-10,-J,-Q,-K,-A", "2:0,3:0,4:0,5:0,6:0,7:0,8:0,9:0,T:0,J:0,Q:0,K:0,A:0"} {
+		if _, err := ParseDeckComposition(spec); err == nil {
+			t.Fatalf("'%s' should fail to parse", spec)
+		}
+	}
+}
+
+func TestGenerateShoeOf(t *testing.T) {
+	shoe := GenerateShoeOf(6, SpanishDeck)
+	if shoe.Remaining() != 6*48 || shoe.Decks() != 6 {
+		t.Fatalf("6 spanish decks should be 288 cards, got %d in %f decks", shoe.Remaining(), shoe.Decks())
+	}
+	comp := shoe.Composition()
+	if comp != SpanishDeck.Composition(6) || comp[10] != 6*12 || comp[11] != 24 {
+		t.Fatalf("spanish shoe should have 12 tens a deck, got %v", comp)
+	}
+	for i := 0; i < 48; i++ {
+		if c := shoe.Deal(); c.Name == "10" {
+			t.Fatalf("spanish deck dealt a 10")
+		}
+	}
+	if shoe.EstimateRemaining() != 5 {
+		t.Fatalf("a deck of 48 cards should be dealt, %f decks left", shoe.EstimateRemaining())
+	}
+
+	infinite := NewInfiniteDeckOf(100, SpanishDeck.With("A", 2)).Seed(1)
+	aces := 0
+	for i := 0; i < 5200; i++ {
+		c := infinite.Deal()
+		if c.Name == "10" {
+			t.Fatalf("infinite spanish deck dealt a 10")
+		}
+		if c.Value == 11 {
+			aces++
+		}
+	}
+	// 8 of every 52 cards are aces
+	if aces < 700 || aces > 900 {
+		t.Fatalf("expected around 800 aces, got %d", aces)
+	}
+}
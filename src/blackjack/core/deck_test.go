@@ -60,3 +60,206 @@ func TestShoeGeneration(t *testing.T) {
 		ValidateDeck(t, shoe, i)
 	}
 }
+
+func TestDeckUnseen(t *testing.T) {
+	d := GenerateShoe(1).Shuffle()
+	for i := 0; i < 10; i++ {
+		d.Deal()
+	}
+	unseen := d.Unseen()
+	if unseen.Remaining() != d.Remaining() {
+		t.Fatalf("expected %d unseen cards, got %d", d.Remaining(), unseen.Remaining())
+	}
+	for i := 0; i < 4; i++ {
+		if c, ok := unseen.Take(11); !ok || c.Value != 11 {
+			if ok {
+				t.Fatalf("took a %d instead of an ace", c.Value)
+			}
+			// some of the aces may have been dealt already
+			break
+		}
+	}
+	cloned := unseen.Clone()
+	if a, b := unseen.Deal(), cloned.Deal(); a != b {
+		t.Fatalf("clone should deal the same cards, got %s and %s", a.ToString(), b.ToString())
+	}
+	if d.Remaining() != DeckSize-10 {
+		t.Fatalf("unseen cards should not be dealt from the shoe")
+	}
+}
+
+func TestDeckContinuousShuffle(t *testing.T) {
+	d := GenerateShoe(1).SetContinuousShuffle(5).Shuffle()
+	for i := 0; i < 10; i++ {
+		d.Deal()
+	}
+	held := append([]Card{}, d.Cards[10:15]...)
+	d.ReturnDiscards()
+	if d.Remaining() != DeckSize {
+		t.Fatalf("discards should go back in, got %d cards", d.Remaining())
+	}
+	for i, c := range held {
+		if dealt := d.Deal(); dealt != c {
+			t.Fatalf("held out card %d should be dealt next, got %s expected %s", i, dealt.ToString(), c.ToString())
+		}
+	}
+	if d.Dealt() != 15 {
+		t.Fatalf("expected 15 cards dealt since the shuffle, got %d", d.Dealt())
+	}
+	d.ReturnDiscards()
+	ValidateDeck(t, d, 1)
+}
+
+func TestDeckCutCard(t *testing.T) {
+	d := GenerateShoe(2).SetCutCard(CutCard{Penetration: 0.75, PercentDealt: true, ExtraRounds: 1}).Shuffle()
+	for i := 0; i < 78; i++ {
+		d.Deal()
+	}
+	d.EndRound()
+	if d.CutCardOut() {
+		t.Fatalf("cut card should sit behind the 78th card")
+	}
+	d.Deal()
+	if !d.CutCardOut() {
+		t.Fatalf("cut card should be out after the 79th card")
+	}
+	d.EndRound()
+	if d.ShoeFinished() {
+		t.Fatalf("shoe should deal an extra round after the cut card")
+	}
+	d.EndRound()
+	if !d.ShoeFinished() {
+		t.Fatalf("shoe should be finished after the extra round")
+	}
+	if d.Shuffle(); d.CutCardOut() || d.ShoeFinished() {
+		t.Fatalf("shuffling should put the cut card back")
+	}
+
+	// placements spread around 1 deck cut off
+	d = GenerateShoe(6).SetCutCard(CutCard{Penetration: 1, StdDev: 10})
+	low, high := d.deckSize, 0
+	for i := 0; i < 1000; i++ {
+		d.Shuffle()
+		if d.cutPosition < low {
+			low = d.cutPosition
+		}
+		if d.cutPosition > high {
+			high = d.cutPosition
+		}
+	}
+	if low > 5*DeckSize-15 || high < 5*DeckSize+15 || high > d.deckSize {
+		t.Fatalf("cut card placements should spread around %d, got %d to %d", 5*DeckSize, low, high)
+	}
+}
+
+func TestDeckBurn(t *testing.T) {
+	previewed := 0
+	d := GenerateShoe(1).SetBurn(2, false).Shuffle()
+	d.PreviewCard = func(c Card) { previewed++ }
+	burned := append([]Card{}, d.Cards[:2]...)
+	d.Deal()
+	if previewed != 1 || d.Remaining() != DeckSize-3 {
+		t.Fatalf("hidden burns should be dealt unseen, previewed %d with %d left", previewed, d.Remaining())
+	}
+	if unseen := d.Unseen(); unseen.Remaining() != DeckSize-1 {
+		t.Fatalf("hidden burns should still be unseen, got %d unseen", unseen.Remaining())
+	} else {
+		for _, b := range burned {
+			if _, ok := unseen.Take(b.Value); !ok {
+				t.Fatalf("burned %s missing from the unseen cards", b.ToString())
+			}
+		}
+	}
+
+	previewed = 0
+	d.SetBurn(2, true).Shuffle()
+	d.Burn()
+	d.Burn()
+	if previewed != 2 || d.Remaining() != DeckSize-2 {
+		t.Fatalf("visible burns should be previewed once, previewed %d with %d left", previewed, d.Remaining())
+	}
+}
+
+func TestDeckComposition(t *testing.T) {
+	d := GenerateShoe(2).SetBurn(1, false).Shuffle()
+	if d.Composition() != NewComposition(2) {
+		t.Fatalf("a fresh shoe should be unseen, got %v", d.Composition())
+	}
+	for i := 0; i < 30; i++ {
+		d.Deal()
+	}
+	// the hidden burn is dealt but still unseen
+	expected := NewComposition(2)
+	for _, c := range d.Cards[1:31] {
+		expected = expected.Remove(c.Value)
+	}
+	if d.Composition() != expected || d.UnseenOf(10) != expected[10] {
+		t.Fatalf("expected %v unseen, got %v", expected, d.Composition())
+	}
+	dealt := d.Cards[31]
+	before := d.UnseenCard(dealt)
+	d.Deal()
+	if d.UnseenCard(dealt) != before-1 {
+		t.Fatalf("dealing %s should leave %d unseen, got %d", dealt.ToString(), before-1, d.UnseenCard(dealt))
+	}
+	if d.Unseen().Composition() != d.Composition() {
+		t.Fatalf("the unseen copy should have the same composition")
+	}
+	d.Shuffle()
+	if d.Composition() != NewComposition(2) || d.UnseenCard(Card{Name: "A", Value: 11, Suit: SuitSpades}) != 2 {
+		t.Fatalf("shuffling should make every card unseen again")
+	}
+}
+
+func TestDealFaceDown(t *testing.T) {
+	d, err := NewScriptedDeck("A T")
+	if err != nil {
+		t.Fatal(err)
+	}
+	previewed := 0
+	d.PreviewCard = func(c Card) {
+		previewed++
+	}
+	hole := d.DealFaceDown()
+	if d.UnseenOf(11) != 1 || previewed != 0 {
+		t.Fatalf("a face down ace should still be unseen, got %d unseen and %d previewed", d.UnseenOf(11), previewed)
+	}
+	d.TurnOver(hole)
+	if d.UnseenOf(11) != 0 || previewed != 1 {
+		t.Fatalf("a turned over ace should be seen, got %d unseen and %d previewed", d.UnseenOf(11), previewed)
+	}
+	d.Deal()
+	if d.UnseenOf(10) != 0 || previewed != 2 {
+		t.Fatalf("a face up card is seen as it's dealt")
+	}
+}
+
+func TestInfiniteDeck(t *testing.T) {
+	var shoe Shoe = NewInfiniteDeck(100).Seed(1)
+	seen := map[int]int{}
+	shoe.SetPreviewCard(func(c Card) { seen[c.Value]++ })
+	for i := 0; i < 99; i++ {
+		shoe.Deal()
+	}
+	if shoe.ShoeFinished() {
+		t.Fatalf("shoe shouldn't finish before 100 cards")
+	}
+	shoe.Deal()
+	if !shoe.ShoeFinished() {
+		t.Fatalf("shoe should finish after 100 cards")
+	}
+	shoe.Reshuffle()
+	for i := 0; i < 130000; i++ {
+		shoe.Deal()
+	}
+	// every value comes up at the same rate no matter how many have been dealt
+	for value := 2; value <= 11; value++ {
+		expected := float64(130100) * float64(NewComposition(1)[value]) / DeckSize
+		if got := float64(seen[value]); got < expected*0.97 || got > expected*1.03 {
+			t.Fatalf("expected around %f %ds, got %f", expected, value, got)
+		}
+	}
+	if shoe.Remaining() != InfiniteDecks*DeckSize {
+		t.Fatalf("an infinite deck never runs down")
+	}
+}
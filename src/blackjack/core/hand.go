@@ -9,6 +9,7 @@ type Hand struct {
 	Doubled       bool
 	SplitHand     bool
 	SplitAcesHand bool
+	Surrendered   bool // given up for half the bet, after doubling this is a double rescue
 }
 
 func (h Hand) HandValue() (int, bool) {
@@ -19,6 +19,9 @@ const (
 	HandResultDealerBlackjack
 	HandResultInsuranceSave
 	HandResultLose
+	HandResultSurrender
+	HandResultRescue // a doubled hand surrendered
+	HandResultBonus  // a 21 paid a bonus payout
 )
 
 func (h OverallHandResult) ToString() string {
@@ -35,6 +38,12 @@ func (h OverallHandResult) ToString() string {
 		return `dealer blackjack`
 	case HandResultWin:
 		return `win`
+	case HandResultSurrender:
+		return `surrender`
+	case HandResultRescue:
+		return `rescue`
+	case HandResultBonus:
+		return `bonus`
 	}
 	return `unknown`
 }
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// ErrOutOfCards is what Deal panics with when a deck runs out
+var ErrOutOfCards = errors.New("out of cards")
+
+// ParseCards reads a card sequence like "A♠ T 6h ♦5 10". Ranks are 2-9, T or
+// 10, J, Q, K and A, the suit is optional and goes either side of the rank as
+// a symbol or one of c, s, d, h. Cards are separated by spaces or commas
+func ParseCards(script string) ([]Card, error) {
+	fields := strings.FieldsFunc(script, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+	parsed := make([]Card, 0, len(fields))
+	for _, field := range fields {
+		c, err := parseCard(field)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, c)
+	}
+	return parsed, nil
+}
+
+func parseCard(field string) (Card, error) {
+	suit := SuitUnknown
+	rank := strings.ToUpper(field)
+	for _, s := range []struct {
+		names []string
+		suit  Suit
+	}{
+		{names: []string{"♣", "C"}, suit: SuitClubs},
+		{names: []string{"♠", "S"}, suit: SuitSpades},
+		{names: []string{"♦", "D"}, suit: SuitDiamonds},
+		{names: []string{"♥", "H"}, suit: SuitHearts},
+	} {
+		for _, name := range s.names {
+			if len(rank) > len(name) && strings.HasPrefix(rank, name) {
+				rank, suit = rank[len(name):], s.suit
+			} else if len(rank) > len(name) && strings.HasSuffix(rank, name) {
+				rank, suit = rank[:len(rank)-len(name)], s.suit
+			}
+		}
+	}
+	if rank == "T" {
+		rank = "10"
+	}
+	for _, c := range cards {
+		if c.Name == rank {
+			c.Suit = suit
+			return c, nil
+		}
+	}
+	return Card{}, fmt.Errorf("invalid card '%s'", field)
+}
+
+// NewScriptedDeck builds a deck that deals the script's cards in order
+func NewScriptedDeck(script string) (*Deck, error) {
+	scripted, err := ParseCards(script)
+	if err != nil {
+		return nil, err
+	}
+	deck := &Deck{
+		Cards:    scripted,
+		deckSize: len(scripted),
+		spec:     StandardDeck,
+		source:   rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+	deck.resetUnseen()
+	return deck, nil
+}
+
+// LoadScriptedDeck reads a deck's script from a file
+func LoadScriptedDeck(path string) (*Deck, error) {
+	script, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewScriptedDeck(string(script))
+}
+
+// SetRandomFallback keeps the deck dealing once it runs out, each card after
+// the last is drawn at random from a full deck of the composition
+func (d *Deck) SetRandomFallback(fallback bool) *Deck {
+	d.fallback = fallback
+	return d
+}
+
+// Next deals the next card, or ErrOutOfCards when there isn't one
+func (d *Deck) Next() (Card, error) {
+	return d.next(true)
+}
+
+func (d *Deck) next(faceUp bool) (Card, error) {
+	d.Burn()
+	if d.idx >= len(d.Cards) {
+		if !d.fallback {
+			return Card{}, ErrOutOfCards
+		}
+		c := d.spec.draw(d.source)
+		d.dealt++
+		if faceUp && d.PreviewCard != nil {
+			d.PreviewCard(c)
+		}
+		return c, nil
+	}
+	return d.deal(faceUp), nil
+}
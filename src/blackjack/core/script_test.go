@@ -0,0 +1,76 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseCards(t *testing.T) {
+	parsed, err := ParseCards("A♠ T, 6h ♦5 10 qc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Card{
+		{Name: "A", Value: 11, Suit: SuitSpades},
+		{Name: "10", Value: 10},
+		{Name: "6", Value: 6, Suit: SuitHearts},
+		{Name: "5", Value: 5, Suit: SuitDiamonds},
+		{Name: "10", Value: 10},
+		{Name: "Q", Value: 10, Suit: SuitClubs},
+	}
+	if len(parsed) != len(expected) {
+		t.Fatalf("expected %d cards, got %d", len(expected), len(parsed))
+	}
+	for i, c := range expected {
+		if parsed[i] != c {
+			t.Fatalf("card %d should be %s, got %s", i, c.ToString(), parsed[i].ToString())
+		}
+	}
+	for _, invalid := range []string{"1", "Z", "♠", "A♠♠"} {
+		if _, err := ParseCards(invalid); err == nil {
+			t.Fatalf("'%s' should not parse", invalid)
+		}
+	}
+}
+
+func TestScriptedDeck(t *testing.T) {
+	d, err := NewScriptedDeck("A K 5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Remaining() != 3 || d.UnseenOf(10) != 1 {
+		t.Fatalf("expected the 3 scripted cards, got %d with %d tens", d.Remaining(), d.UnseenOf(10))
+	}
+	for _, value := range []int{11, 10, 5} {
+		if c := d.Deal(); c.Value != value {
+			t.Fatalf("expected %d, got %s", value, c.ToString())
+		}
+	}
+	if _, err := d.Next(); !errors.Is(err, ErrOutOfCards) {
+		t.Fatalf("expected out of cards, got %v", err)
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != ErrOutOfCards {
+				t.Fatalf("Deal should panic with out of cards, got %v", r)
+			}
+		}()
+		d.Deal()
+	}()
+
+	d.SetRandomFallback(true)
+	for i := 0; i < 100; i++ {
+		if c := d.Deal(); c.Value < 2 || c.Value > 11 || c.Suit == SuitUnknown {
+			t.Fatalf("fallback dealt an invalid card %s", c.ToString())
+		}
+	}
+}
+
+func TestRandomFallbackComposition(t *testing.T) {
+	d := GenerateShoeOf(1, SpanishDeck).SetRandomFallback(true)
+	for i := 0; i < 500; i++ {
+		if c := d.Deal(); c.Name == "10" {
+			t.Fatalf("a Spanish deck's fallback shouldn't deal tens")
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package core
+
+import "math/rand/v2"
+
+// Shoe is what the game deals from, a Deck or an InfiniteDeck
+type Shoe interface {
+	Deal() Card
+	Remaining() int
+	EstimateRemaining() float32
+	// Decks is the number of decks the shoe was built from
+	Decks() float32
+	Composition() Composition
+	SetPreviewCard(preview func(c Card))
+	// DealFaceDown deals a card the players don't see, it isn't previewed
+	// until it's turned over
+	DealFaceDown() Card
+	TurnOver(c Card)
+
+	// Burn burns any cards due off the top after a shuffle
+	Burn()
+	// EndRound is called after every round
+	EndRound()
+	// ShoeFinished is true once the shoe is due a shuffle
+	ShoeFinished() bool
+	ContinuousShuffle() bool
+	// Reshuffle gathers the cards back up ready for the next shoe
+	Reshuffle()
+}
+
+func (d *Deck) SetPreviewCard(preview func(c Card)) {
+	d.PreviewCard = preview
+}
+
+// DealFaceDown deals the next card without it being seen, it stays in the
+// unseen composition until it's turned over
+func (d *Deck) DealFaceDown() Card {
+	c, err := d.next(false)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func (d *Deck) TurnOver(c Card) {
+	for i, down := range d.faceDown {
+		if down == c {
+			d.faceDown = append(d.faceDown[:i], d.faceDown[i+1:]...)
+			d.see(c, -1)
+			break
+		}
+	}
+	if d.PreviewCard != nil {
+		d.PreviewCard(c)
+	}
+}
+
+func (d *Deck) Reshuffle() {
+	d.Shuffle()
+}
+
+// InfiniteDecks is the size of deck an infinite deck reports, large enough for
+// every count to sit at zero
+const InfiniteDecks = 1000
+
+// InfiniteDeck draws every card independently from a full deck of Spec, so the odds
+// never change as cards are dealt. It has no shoes, ShoeSize cards are dealt
+// between shuffles to give the game the same breaks as a dealt shoe
+type InfiniteDeck struct {
+	PreviewCard func(c Card)
+	ShoeSize    int
+	Spec        DeckComposition
+	dealt       int
+	source      *rand.Rand
+}
+
+func NewInfiniteDeck(shoeSize int) *InfiniteDeck {
+	return NewInfiniteDeckOf(shoeSize, StandardDeck)
+}
+
+func NewInfiniteDeckOf(shoeSize int, spec DeckComposition) *InfiniteDeck {
+	return &InfiniteDeck{
+		ShoeSize: shoeSize,
+		Spec:     spec,
+		source:   rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+}
+
+// Seed reseeds the draws so runs can be repeated
+func (d *InfiniteDeck) Seed(seed uint64) *InfiniteDeck {
+	d.source = rand.New(rand.NewPCG(seed, seed))
+	return d
+}
+
+func (d *InfiniteDeck) Deal() Card {
+	c := d.Spec.draw(d.source)
+	d.dealt++
+	if d.PreviewCard != nil {
+		d.PreviewCard(c)
+	}
+	return c
+}
+
+func (d *InfiniteDeck) Remaining() int {
+	return InfiniteDecks * d.Spec.Size()
+}
+
+func (d *InfiniteDeck) EstimateRemaining() float32 {
+	return InfiniteDecks
+}
+
+func (d *InfiniteDeck) Decks() float32 {
+	return InfiniteDecks
+}
+
+func (d *InfiniteDeck) Composition() Composition {
+	return d.Spec.Composition(InfiniteDecks)
+}
+
+func (d *InfiniteDeck) SetPreviewCard(preview func(c Card)) {
+	d.PreviewCard = preview
+}
+
+func (d *InfiniteDeck) DealFaceDown() Card {
+	c := d.Spec.draw(d.source)
+	d.dealt++
+	return c
+}
+
+func (d *InfiniteDeck) TurnOver(c Card) {
+	if d.PreviewCard != nil {
+		d.PreviewCard(c)
+	}
+}
+
+func (d *InfiniteDeck) Burn() {}
+
+func (d *InfiniteDeck) EndRound() {}
+
+func (d *InfiniteDeck) ShoeFinished() bool {
+	return d.dealt >= d.ShoeSize
+}
+
+func (d *InfiniteDeck) ContinuousShuffle() bool {
+	return false
+}
+
+func (d *InfiniteDeck) Reshuffle() {
+	d.dealt = 0
+}
@@ -0,0 +1,291 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// Shuffler reorders the shoe. It's handed the card positions in the order
+// they're picked up, discards first then the unplayed cards, and rearranges
+// them into the order they'll be dealt
+type Shuffler interface {
+	Shuffle(order []int, source *rand.Rand)
+	ToString() string
+}
+
+// RandomShuffle is a perfect shuffle, every order is equally likely
+type RandomShuffle struct{}
+
+func (RandomShuffle) Shuffle(order []int, source *rand.Rand) {
+	source.Shuffle(len(order), func(i, j int) {
+		order[i], order[j] = order[j], order[i]
+	})
+}
+
+func (RandomShuffle) ToString() string {
+	return "random"
+}
+
+// Riffle splits the cards near the middle and interleaves the halves. Cards
+// drop from each half in proportion to what's left in it, Clump is the extra
+// chance the next card drops from the same half as the last one
+type Riffle struct {
+	Clump float64
+}
+
+func (r Riffle) Shuffle(order []int, source *rand.Rand) {
+	n := len(order)
+	if n < 2 {
+		return
+	}
+	cut := n/2 + int(math.Round(source.NormFloat64()*math.Sqrt(float64(n))/2))
+	cut = clampInt(cut, 1, n-1)
+	left := append([]int{}, order[:cut]...)
+	right := append([]int{}, order[cut:]...)
+
+	// cards drop from the bottom of each half, so the shuffled cards build up from the bottom
+	lastLeft := false
+	for i := n - 1; i >= 0; i-- {
+		fromLeft := len(right) == 0
+		if len(left) > 0 && len(right) > 0 {
+			if i < n-1 && source.Float64() < r.Clump {
+				fromLeft = lastLeft
+			} else {
+				fromLeft = source.IntN(len(left)+len(right)) < len(left)
+			}
+		}
+		if fromLeft {
+			order[i] = left[len(left)-1]
+			left = left[:len(left)-1]
+		} else {
+			order[i] = right[len(right)-1]
+			right = right[:len(right)-1]
+		}
+		lastLeft = fromLeft
+	}
+}
+
+func (r Riffle) ToString() string {
+	if r.Clump > 0 {
+		return fmt.Sprintf("riffle:%g", r.Clump)
+	}
+	return "riffle"
+}
+
+// Strip pulls packets off the top onto a new pile, reversing their order
+type Strip struct {
+	Packets int
+}
+
+func (s Strip) Shuffle(order []int, source *rand.Rand) {
+	n := len(order)
+	if s.Packets < 2 || n < 2 {
+		return
+	}
+	mean := float64(n) / float64(s.Packets)
+	packets := [][]int{}
+	for start := 0; start < n; {
+		size := clampInt(int(math.Round(mean*(0.5+source.Float64()))), 1, n-start)
+		packets = append(packets, append([]int{}, order[start:start+size]...))
+		start += size
+	}
+	i := 0
+	for p := len(packets) - 1; p >= 0; p-- {
+		i += copy(order[i:], packets[p])
+	}
+}
+
+func (s Strip) ToString() string {
+	return fmt.Sprintf("strip:%d", s.Packets)
+}
+
+// Plug takes Cards off the bottom and plugs them in somewhere in the rest
+type Plug struct {
+	Cards int
+}
+
+func (p Plug) Shuffle(order []int, source *rand.Rand) {
+	n := len(order)
+	plugged := clampInt(p.Cards, 0, n-2)
+	if plugged == 0 {
+		return
+	}
+	rest := n - plugged
+	at := 1 + source.IntN(rest-1)
+	bottom := append([]int{}, order[rest:]...)
+	copy(order[at+plugged:], order[at:rest])
+	copy(order[at:], bottom)
+}
+
+func (p Plug) ToString() string {
+	return fmt.Sprintf("plug:%d", p.Cards)
+}
+
+// Cut moves the cards above a point somewhere in the middle half to the bottom
+type Cut struct{}
+
+func (Cut) Shuffle(order []int, source *rand.Rand) {
+	n := len(order)
+	if n < 4 {
+		return
+	}
+	at := n/4 + source.IntN(n/2)
+	cut := append(append([]int{}, order[at:]...), order[:at]...)
+	copy(order, cut)
+}
+
+func (Cut) ToString() string {
+	return "cut"
+}
+
+// Zone splits the cards into two stacks and works down them a grab at a time.
+// Each pair of grabs is put through the steps and stacked onto the shuffled pile,
+// so cards never travel further than the zone they were grabbed in
+type Zone struct {
+	Grab  int // cards grabbed off each stack
+	Steps Procedure
+}
+
+func (z Zone) Shuffle(order []int, source *rand.Rand) {
+	n := len(order)
+	if z.Grab < 1 || n < 2 {
+		return
+	}
+	left := append([]int{}, order[:n/2]...)
+	right := append([]int{}, order[n/2:]...)
+	packets := [][]int{}
+	for len(left) > 0 || len(right) > 0 {
+		l, r := clampInt(z.Grab, 0, len(left)), clampInt(z.Grab, 0, len(right))
+		packet := append(append([]int{}, left[:l]...), right[:r]...)
+		left, right = left[l:], right[r:]
+		z.Steps.Shuffle(packet, source)
+		packets = append(packets, packet)
+	}
+	i := 0
+	for p := len(packets) - 1; p >= 0; p-- {
+		i += copy(order[i:], packets[p])
+	}
+}
+
+func (z Zone) ToString() string {
+	return fmt.Sprintf("zone:%d(%s)", z.Grab, z.Steps.ToString())
+}
+
+// Procedure is a casino's shuffle, its steps are done in order
+type Procedure []Shuffler
+
+func (p Procedure) Shuffle(order []int, source *rand.Rand) {
+	for _, step := range p {
+		step.Shuffle(order, source)
+	}
+}
+
+func (p Procedure) ToString() string {
+	steps := make([]string, len(p))
+	for i, step := range p {
+		steps[i] = step.ToString()
+	}
+	return strings.Join(steps, ",")
+}
+
+// ParseShuffle reads a shuffle procedure, steps are comma separated and done
+// in order: random, riffle[:clump], strip:packets, plug:cards, cut and
+// zone:grab(steps). e.g. "zone:52(riffle,strip:4,riffle),cut"
+func ParseShuffle(desc string) (Procedure, error) {
+	procedure := Procedure{}
+	for _, step := range splitSteps(desc) {
+		parsed, err := parseStep(step)
+		if err != nil {
+			return nil, err
+		}
+		procedure = append(procedure, parsed)
+	}
+	if len(procedure) == 0 {
+		return nil, fmt.Errorf("no shuffle steps in '%s'", desc)
+	}
+	return procedure, nil
+}
+
+// splitSteps splits on the commas outside of a zone's steps
+func splitSteps(desc string) []string {
+	steps := []string{}
+	depth, start := 0, 0
+	for i, c := range desc {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				steps = append(steps, strings.TrimSpace(desc[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(desc[start:]); last != "" {
+		steps = append(steps, last)
+	}
+	return steps
+}
+
+func parseStep(step string) (Shuffler, error) {
+	name, arg := step, ""
+	if i := strings.Index(step, ":"); i >= 0 {
+		name, arg = step[:i], step[i+1:]
+	}
+	switch strings.ToLower(name) {
+	case "random":
+		return RandomShuffle{}, nil
+	case "riffle":
+		if arg == "" {
+			return Riffle{}, nil
+		}
+		clump, err := strconv.ParseFloat(arg, 64)
+		if err != nil || clump < 0 || clump >= 1 {
+			return nil, fmt.Errorf("invalid riffle clumping '%s'", arg)
+		}
+		return Riffle{Clump: clump}, nil
+	case "strip":
+		packets, err := strconv.Atoi(arg)
+		if err != nil || packets < 2 {
+			return nil, fmt.Errorf("invalid strip packets '%s'", arg)
+		}
+		return Strip{Packets: packets}, nil
+	case "plug":
+		cards, err := strconv.Atoi(arg)
+		if err != nil || cards < 1 {
+			return nil, fmt.Errorf("invalid plug size '%s'", arg)
+		}
+		return Plug{Cards: cards}, nil
+	case "cut":
+		return Cut{}, nil
+	case "zone":
+		open, close := strings.Index(arg, "("), strings.LastIndex(arg, ")")
+		if open < 0 || close < open {
+			return nil, fmt.Errorf("zone needs its steps in brackets, got '%s'", arg)
+		}
+		grab, err := strconv.Atoi(arg[:open])
+		if err != nil || grab < 1 {
+			return nil, fmt.Errorf("invalid zone grab '%s'", arg[:open])
+		}
+		steps, err := ParseShuffle(arg[open+1 : close])
+		if err != nil {
+			return nil, err
+		}
+		return Zone{Grab: grab, Steps: steps}, nil
+	}
+	return nil, fmt.Errorf("unknown shuffle step '%s'", step)
+}
+
+func clampInt(v, low, high int) int {
+	if v < low {
+		return low
+	} else if v > high {
+		return high
+	}
+	return v
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestShufflers(t *testing.T) {
+	source := rand.New(rand.NewPCG(1, 2))
+	shufflers := []Shuffler{RandomShuffle{}, Riffle{}, Riffle{Clump: 0.5}, Strip{Packets: 5}, Plug{Cards: 52}, Cut{},
+		Zone{Grab: 26, Steps: Procedure{Riffle{}, Strip{Packets: 3}}}}
+	for _, shuffler := range shufflers {
+		order := make([]int, 6*DeckSize)
+		for i := range order {
+			order[i] = i
+		}
+		shuffler.Shuffle(order, source)
+		seen := map[int]bool{}
+		moved := 0
+		for i, from := range order {
+			seen[from] = true
+			if i != from {
+				moved++
+			}
+		}
+		if len(seen) != len(order) {
+			t.Fatalf("%s lost cards, %d of %d left", shuffler.ToString(), len(seen), len(order))
+		}
+		if moved == 0 {
+			t.Fatalf("%s didn't move any cards", shuffler.ToString())
+		}
+	}
+}
+
+func TestZoneShuffleKeepsZones(t *testing.T) {
+	// two 52 card grabs off each half of a 4 deck shoe, the first pair ends up at the bottom
+	order := make([]int, 4*DeckSize)
+	for i := range order {
+		order[i] = i
+	}
+	Zone{Grab: DeckSize, Steps: Procedure{Riffle{}, Riffle{}}}.Shuffle(order, rand.New(rand.NewPCG(1, 2)))
+	for i, from := range order {
+		bottom := i >= 2*DeckSize
+		firstGrabs := from < DeckSize || (from >= 2*DeckSize && from < 3*DeckSize)
+		if bottom != firstGrabs {
+			t.Fatalf("card %d left its zone, dealt %dth", from, i)
+		}
+	}
+}
+
+func TestParseShuffle(t *testing.T) {
+	desc := "zone:52(riffle:0.25,strip:4,riffle),plug:26,cut,random"
+	shuffler, err := ParseShuffle(desc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shuffler.ToString() != desc {
+		t.Fatalf("expected %s, got %s", desc, shuffler.ToString())
+	}
+	for _, invalid := range []string{"", "shuffle", "strip:1", "riffle:2", "zone:52", "zone:x(riffle)", "zone:52(bad)"} {
+		if _, err := ParseShuffle(invalid); err == nil {
+			t.Fatalf("'%s' should not parse", invalid)
+		}
+	}
+}
+
+func TestDeckShuffler(t *testing.T) {
+	d := GenerateShoe(2).Shuffle().SetShuffler(Procedure{Riffle{}, Strip{Packets: 4}, Cut{}})
+	for i := 0; i < 50; i++ {
+		d.Deal()
+	}
+	d.Shuffle()
+	ValidateDeck(t, d, 2)
+}
@@ -0,0 +1,123 @@
+package strategies
+
+import "github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+
+// AceSequencingStrategy memorizes the two cards dealt just before each ace and
+// watches for them after the shuffle. A shuffle that doesn't pull neighbours far
+// apart brings the ace out shortly after its key cards, so when they show the
+// player bets big and spreads to enough spots to steer the ace onto one of
+// them. The rest of the time it bets off the HiLo count. Keying on a pair keeps
+// the other copies of a card in a multi-deck shoe from calling for the ace
+type AceSequencingStrategy struct {
+	Keys   int     // aces memorized per shoe
+	Window int     // cards after the key card the ace is expected within
+	Spots  int     // most spots played to steer the ace
+	BigBet float32 // units bet per spot when an ace is due
+
+	SequencedBids int // rounds bet on a sequenced ace
+	SequencedAces int // aces that came out within the window of their key card
+	KeyCards      int // key cards spotted
+
+	counter   *HighLowCountStrategy
+	keys      map[keyCards]int // key cards of the shoe being dealt
+	next      map[keyCards]int // key cards memorized for the next shoe
+	memorized int              // aces memorized for the next shoe
+	last      keyCards         // the last two cards dealt
+	due       bool
+	sinceKey  int
+}
+
+// keyCards are the two cards dealt before an ace, in order
+type keyCards [2]core.Card
+
+func InitAceSequencing(counter *HighLowCountStrategy, keys int, window int, spots int, bigBet float32) *AceSequencingStrategy {
+	return &AceSequencingStrategy{
+		Keys:    keys,
+		Window:  window,
+		Spots:   spots,
+		BigBet:  bigBet,
+		counter: counter,
+		keys:    map[keyCards]int{},
+		next:    map[keyCards]int{},
+	}
+}
+
+func (strat *AceSequencingStrategy) Instance() TrackingStrategy {
+	return InitAceSequencing(strat.counter.Instance().(*HighLowCountStrategy), strat.Keys, strat.Window, strat.Spots, strat.BigBet)
+}
+
+func (strat *AceSequencingStrategy) SetBankroll(bankroll float32) {
+	strat.counter.SetBankroll(bankroll)
+}
+
+func (strat *AceSequencingStrategy) SizesByBankroll() bool {
+	return strat.counter.SizesByBankroll()
+}
+
+func (strat *AceSequencingStrategy) SetDeck(spec core.DeckComposition) {
+	strat.counter.SetDeck(spec)
+}
+
+func (strat *AceSequencingStrategy) Miscount(delta int) {
+	strat.counter.Miscount(delta)
+}
+
+func (strat *AceSequencingStrategy) Update(cards ...core.Card) {
+	strat.counter.Update(cards...)
+	for _, c := range cards {
+		if strat.due {
+			strat.sinceKey++
+			if strat.sinceKey > strat.Window {
+				strat.due = false
+			}
+		}
+		if c.Value == 11 {
+			if strat.due {
+				strat.SequencedAces++
+				strat.due = false
+			}
+			if strat.last[0].Value != 0 && strat.memorized < strat.Keys {
+				strat.next[strat.last]++
+				strat.memorized++
+			}
+		}
+		strat.last = keyCards{strat.last[1], c}
+		if strat.keys[strat.last] > 0 {
+			strat.keys[strat.last]--
+			strat.KeyCards++
+			strat.due = true
+			strat.sinceKey = 0
+		}
+	}
+}
+
+// Shuffle swaps in the key cards memorized from the discards
+func (strat *AceSequencingStrategy) Shuffle() {
+	strat.counter.Shuffle()
+	strat.keys = strat.next
+	strat.next = map[keyCards]int{}
+	strat.memorized = 0
+	strat.last = keyCards{}
+	strat.due = false
+}
+
+func (strat *AceSequencingStrategy) TrueCount(d core.Shoe) int {
+	return strat.counter.TrueCount(d)
+}
+
+// Bid bets big when the ace is due, the player's first cards are the next ones
+// dealt so playing a spot for each card left in the window puts the ace on one
+func (strat *AceSequencingStrategy) Bid(d core.Shoe) BidStrategy {
+	if !strat.due {
+		return strat.counter.Bid(d)
+	}
+	spots := strat.Window - strat.sinceKey
+	if spots > strat.Spots {
+		spots = strat.Spots
+	}
+	if spots < 1 {
+		return strat.counter.Bid(d)
+	}
+	strat.SequencedBids++
+	return BidStrategy{Hands: spots, Units: strat.BigBet}
+}
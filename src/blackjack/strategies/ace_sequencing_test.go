@@ -0,0 +1,46 @@
+package strategies
+
+import (
+	"testing"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+)
+
+func TestAceSequencing(t *testing.T) {
+	deck := core.GenerateShoe(1)
+	five := core.Card{Name: "5", Value: 5, Suit: core.SuitClubs}
+	key := core.Card{Name: "K", Value: 10, Suit: core.SuitSpades}
+	ace := core.Card{Name: "A", Value: 11, Suit: core.SuitHearts}
+	strat := InitAceSequencing(InitHighLow(map[int]BidStrategy{}), 1, 3, 2, 10)
+	// only the first ace is memorized
+	strat.Update(five, key, ace, core.Card{Name: "2", Value: 2}, core.Card{Name: "3", Value: 3}, ace)
+	strat.Shuffle()
+
+	strat.Update(core.Card{Name: "K", Value: 10, Suit: core.SuitHearts})
+	if bid := strat.Bid(deck); bid.Units != 1 {
+		t.Fatalf("only the memorized key cards should call for a big bet, got %f", bid.Units)
+	}
+	strat.Update(core.Card{Name: "9", Value: 9}, key)
+	if bid := strat.Bid(deck); bid.Units != 1 {
+		t.Fatalf("another copy's neighbour shouldn't call for a big bet, got %f", bid.Units)
+	}
+	strat.Update(five, key)
+	if bid := strat.Bid(deck); bid.Hands != 2 || bid.Units != 10 {
+		t.Fatalf("expected 2 spots of 10 units with the ace due, got %d of %f", bid.Hands, bid.Units)
+	}
+	strat.Update(core.Card{Name: "9", Value: 9}, core.Card{Name: "8", Value: 8})
+	if bid := strat.Bid(deck); bid.Hands != 1 {
+		t.Fatalf("one card left in the window should be one spot, got %d", bid.Hands)
+	}
+	strat.Update(ace)
+	if strat.SequencedAces != 1 || strat.KeyCards != 1 {
+		t.Fatalf("expected the ace to follow its key card, got %d aces from %d key cards", strat.SequencedAces, strat.KeyCards)
+	}
+	if bid := strat.Bid(deck); bid.Hands != 1 || bid.Units != 1 {
+		t.Fatalf("should go back to counting once the ace is out, got %d of %f", bid.Hands, bid.Units)
+	}
+	strat.Update(core.Card{Name: "2", Value: 2}, core.Card{Name: "3", Value: 3})
+	if strat.KeyCards != 1 {
+		t.Fatalf("the second ace is past the keys memorized, got %d key cards", strat.KeyCards)
+	}
+}
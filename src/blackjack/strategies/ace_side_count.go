@@ -0,0 +1,70 @@
+package strategies
+
+import "github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+
+const acesPerDeck = 4
+
+// AceSideCount tracks aces separately from the main count. Level one counts
+// lump aces in with tens, the side count lets betting account for a shoe that
+// is rich or poor in aces
+type AceSideCount struct {
+	Adjustment   float32 // running count adjustment per surplus ace when betting
+	AcesSeen     int
+	AdjustedBids int // bids that landed on a different count because of the side count
+	acesPerDeck  float32
+}
+
+func NewAceSideCount(adjustment float32) *AceSideCount {
+	return &AceSideCount{Adjustment: adjustment, acesPerDeck: acesPerDeck}
+}
+
+// SetDeck counts off the aces in each deck of the composition
+func (a *AceSideCount) SetDeck(spec core.DeckComposition) {
+	a.acesPerDeck = float32(spec.Composition(1)[11])
+}
+
+func (a *AceSideCount) Instance() *AceSideCount {
+	if a == nil {
+		return nil
+	}
+	created := NewAceSideCount(a.Adjustment)
+	created.acesPerDeck = a.acesPerDeck
+	return created
+}
+
+func (a *AceSideCount) Update(c core.Card) {
+	if c.Value == 11 {
+		a.AcesSeen++
+	}
+}
+
+func (a *AceSideCount) Shuffle() {
+	a.AcesSeen = 0
+}
+
+// Surplus is the aces remaining over what a neutral shoe would hold, negative when ace poor
+func (a *AceSideCount) Surplus(d core.Shoe) float32 {
+	remaining := a.acesPerDeck*d.Decks() - float32(a.AcesSeen)
+	return remaining - a.acesPerDeck*d.EstimateRemaining()
+}
+
+// Density is the aces remaining per deck, 4 in a neutral standard shoe
+func (a *AceSideCount) Density(d core.Shoe) float32 {
+	decks := d.EstimateRemaining()
+	if decks <= 0 {
+		return 0
+	}
+	return (a.acesPerDeck*d.Decks() - float32(a.AcesSeen)) / decks
+}
+
+// InsuranceAdjustment is added to the true count before insuring. Level one
+// counts tag aces with the tens, but only a ten makes insurance pay, so the
+// aces' share of the count is taken back out
+func (a *AceSideCount) InsuranceAdjustment(d core.Shoe) float32 {
+	return a.acesPerDeck - a.Density(d)
+}
+
+// BettingAdjustment is added to the running count before betting
+func (a *AceSideCount) BettingAdjustment(d core.Shoe) float32 {
+	return a.Adjustment * a.Surplus(d)
+}
@@ -0,0 +1,46 @@
+package strategies
+
+import (
+	"testing"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+)
+
+func TestAceSideCount(t *testing.T) {
+	strat := InitHighLow(map[int]BidStrategy{})
+	strat.AceSideCount = NewAceSideCount(1)
+
+	// an unshuffled deck deals 2 through K of clubs first, no aces
+	d := core.GenerateShoe(1)
+	d.PreviewCard = func(c core.Card) { strat.Update(c) }
+	for i := 0; i < 12; i++ {
+		d.Deal()
+	}
+	// 4 aces left in 40 cards vs the 3.08 expected
+	if surplus := strat.AceSideCount.Surplus(d); surplus < 0.92 || surplus > 0.93 {
+		t.Fatalf("expected ~0.92 surplus aces, got %f", surplus)
+	}
+	if density := strat.AceSideCount.Density(d); density < 5.19 || density > 5.21 {
+		t.Fatalf("expected ~5.2 aces per deck, got %f", density)
+	}
+	if strat.trueCount(d) <= strat.PlayingTrueCount(d) {
+		t.Fatalf("an ace rich shoe should raise the betting count")
+	}
+	// the spare ace per deck left doesn't help insurance
+	if adj := strat.AceSideCount.InsuranceAdjustment(d); adj < -1.21 || adj > -1.19 {
+		t.Fatalf("expected ~-1.2 off the insurance count, got %f", adj)
+	}
+	if strat.InsuranceTrueCount(d) >= strat.PlayingTrueCount(d) {
+		t.Fatalf("an ace rich shoe should lower the insurance count")
+	}
+
+	d.Deal() // ace of clubs
+	if surplus := strat.AceSideCount.Surplus(d); surplus < -0.001 || surplus > 0.001 {
+		t.Fatalf("a full suit dealt should be ace neutral, got %f", surplus)
+	}
+
+	strat.Shuffle()
+	if strat.AceSideCount.AcesSeen != 0 {
+		t.Fatalf("shuffle should reset the side count")
+	}
+}
@@ -0,0 +1,164 @@
+package strategies
+
+import (
+	"math"
+	"sort"
+)
+
+// TCEstimate is the per-unit expectation and variance of a round played at a
+// given true count, usually taken from a flatbet calibration sim
+type TCEstimate struct {
+	Frequency float64 // share of rounds played at this count
+	EV        float64
+	Variance  float64
+}
+
+type RampObjective int
+
+const (
+	RampObjectiveKelly RampObjective = iota
+	RampObjectiveSCORE
+)
+
+// SCORE is defined as the win rate of a full kelly bettor with a $10,000 bankroll
+const scoreBankroll = 10000
+
+// Counts seen less often than this are too noisy to size a bet from
+const minRampFrequency = 0.0005
+
+// Correlation between two spots played against the same dealer hand
+const DefaultSpotCorrelation = 0.5
+
+type BetRampParams struct {
+	UnitSize        float32 // bets are whole multiples of this, the table min when unset
+	TableMin        float32
+	MaxSpread       float32 // max total action as a multiple of the table min, 0 for none
+	TableMax        float32 // 0 for no max
+	Bankroll        float32
+	KellyFraction   float32
+	Objective       RampObjective
+	SpotCorrelation float32
+}
+
+type BetRamp struct {
+	Spread   map[int]BidStrategy // in units of UnitSize
+	EVPer100 float64             // in currency
+	SDPer100 float64             // in currency
+	DI       float64
+}
+
+// OptimalBetRamp picks the kelly optimal bet for every true count, rounded to
+// whole units, and plays two spots where that grows the bankroll faster
+func OptimalBetRamp(estimates map[int]TCEstimate, params BetRampParams) BetRamp {
+	kellyBankroll := float64(params.Bankroll * params.KellyFraction)
+	if params.Objective == RampObjectiveSCORE {
+		kellyBankroll = scoreBankroll
+	}
+	unit := float64(params.UnitSize)
+	if unit <= 0 {
+		unit = float64(params.TableMin)
+	}
+	minBet := math.Max(float64(params.TableMin), unit)
+	maxSpotBet := math.Inf(1)
+	if params.TableMax > 0 {
+		maxSpotBet = float64(params.TableMax)
+	}
+	maxAction := math.Inf(1)
+	if params.MaxSpread > 0 {
+		maxAction = minBet * float64(params.MaxSpread)
+	}
+	limits := rampLimits{
+		unit:       unit,
+		minUnits:   math.Ceil(minBet / unit),
+		maxSpotBet: math.Min(maxSpotBet, maxAction),
+		maxAction:  maxAction,
+	}
+
+	counts := make([]int, 0, len(estimates))
+	for tc := range estimates {
+		counts = append(counts, tc)
+	}
+	sort.Ints(counts)
+
+	ramp := BetRamp{Spread: map[int]BidStrategy{}}
+	minBid := BidStrategy{Hands: 1, Units: float32(limits.minUnits)}
+	previous := minBid
+	roundEV, roundEVSquared := 0.0, 0.0
+	for _, tc := range counts {
+		est := estimates[tc]
+		bid := minBid
+		if tc >= 0 && est.Frequency >= minRampFrequency && est.EV > 0 && est.Variance > 0 {
+			bid = kellyBid(est, kellyBankroll, limits, float64(params.SpotCorrelation))
+		}
+		// never bet less at a higher count, estimates at the extremes are noisy
+		if bid.Units*float32(bid.Hands) < previous.Units*float32(previous.Hands) {
+			bid = previous
+		}
+		previous = bid
+		if tc >= 0 && est.Frequency >= minRampFrequency {
+			ramp.Spread[tc] = bid
+		}
+
+		spots := float64(bid.Hands)
+		bet := float64(bid.Units) * unit
+		ev := spots * bet * est.EV
+		variance := spots * bet * bet * est.Variance * (1 + (spots-1)*float64(params.SpotCorrelation))
+		roundEV += est.Frequency * ev
+		roundEVSquared += est.Frequency * (variance + ev*ev)
+	}
+	trimRamp(ramp.Spread)
+
+	roundVariance := roundEVSquared - roundEV*roundEV
+	ramp.EVPer100 = roundEV * 100
+	ramp.SDPer100 = math.Sqrt(roundVariance * 100)
+	if roundVariance > 0 {
+		ramp.DI = roundEV / math.Sqrt(roundVariance) * 1000
+	}
+	return ramp
+}
+
+type rampLimits struct {
+	unit       float64
+	minUnits   float64
+	maxSpotBet float64
+	maxAction  float64
+}
+
+// kellyBid compares the best single spot bet against the best pair of spots
+// using the kelly growth rate, EV - Var/2B
+func kellyBid(est TCEstimate, bankroll float64, limits rampLimits, correlation float64) BidStrategy {
+	clamp := func(bet, max float64) float64 {
+		units := math.Round(bet / limits.unit)
+		return math.Max(limits.minUnits, math.Min(units, math.Floor(max/limits.unit)))
+	}
+	growth := func(spots, units float64) float64 {
+		bet := units * limits.unit
+		variance := spots * bet * bet * est.Variance * (1 + (spots-1)*correlation)
+		return spots*bet*est.EV - variance/(2*bankroll)
+	}
+
+	oneSpot := clamp(bankroll*est.EV/est.Variance, limits.maxSpotBet)
+	twoSpots := clamp(bankroll*est.EV/(est.Variance*(1+correlation)), math.Min(limits.maxSpotBet, limits.maxAction/2))
+	if limits.maxAction/2 >= limits.minUnits*limits.unit && growth(2, twoSpots) > growth(1, oneSpot) {
+		return BidStrategy{Hands: 2, Units: float32(twoSpots)}
+	}
+	return BidStrategy{Hands: 1, Units: float32(oneSpot)}
+}
+
+// trimRamp drops the tail of the ramp once it has topped out, Bidspread already
+// bets the highest entry for every count above it
+func trimRamp(spread map[int]BidStrategy) {
+	maxTC := math.MinInt32
+	for tc := range spread {
+		if tc > maxTC {
+			maxTC = tc
+		}
+	}
+	for tc := maxTC - 1; ; tc-- {
+		bid, exists := spread[tc]
+		if !exists || bid != spread[tc+1] {
+			return
+		}
+		delete(spread, tc+1)
+	}
+}
@@ -0,0 +1,96 @@
+package strategies
+
+import "testing"
+
+func testEstimates() map[int]TCEstimate {
+	return map[int]TCEstimate{
+		-2: {Frequency: 0.15, EV: -0.015, Variance: 1.3},
+		-1: {Frequency: 0.2, EV: -0.01, Variance: 1.3},
+		0:  {Frequency: 0.3, EV: -0.005, Variance: 1.3},
+		1:  {Frequency: 0.15, EV: 0.0, Variance: 1.3},
+		2:  {Frequency: 0.1, EV: 0.005, Variance: 1.3},
+		3:  {Frequency: 0.05, EV: 0.01, Variance: 1.3},
+		4:  {Frequency: 0.03, EV: 0.015, Variance: 1.3},
+		5:  {Frequency: 0.02, EV: 0.02, Variance: 1.3},
+	}
+}
+
+func TestOptimalBetRamp(t *testing.T) {
+	ramp := OptimalBetRamp(testEstimates(), BetRampParams{
+		TableMin:        10,
+		TableMax:        1000,
+		MaxSpread:       100,
+		Bankroll:        10000,
+		KellyFraction:   1,
+		SpotCorrelation: DefaultSpotCorrelation,
+	})
+	if _, exists := ramp.Spread[-1]; exists {
+		t.Fatalf("negative counts should fall through to the min bet")
+	}
+	if ramp.Spread[0].Units != 1 || ramp.Spread[1].Units != 1 {
+		t.Fatalf("should min bet without an advantage, got %+v", ramp.Spread)
+	}
+	// a single spot at TC 2 is 10000 * 0.005 / 1.3 ~= $38, but two correlated
+	// spots of 10000 * 0.005 / (1.3 * 1.5) ~= $26 grow the bankroll faster
+	if bid := ramp.Spread[2]; bid.Units != 3 || bid.Hands != 2 {
+		t.Fatalf("expected 3 units on two spots at TC 2, got %+v", bid)
+	}
+	last := float32(0)
+	for tc := 0; tc <= 5; tc++ {
+		bid, exists := ramp.Spread[tc]
+		if !exists {
+			continue
+		}
+		if action := bid.Units * float32(bid.Hands); action < last {
+			t.Fatalf("ramp should never decrease, TC %d bets %f after %f", tc, action, last)
+		} else {
+			last = action
+		}
+	}
+	if ramp.EVPer100 <= 0 {
+		t.Fatalf("ramp should have a positive expectation, got %f", ramp.EVPer100)
+	}
+}
+
+func TestOptimalBetRampLimits(t *testing.T) {
+	ramp := OptimalBetRamp(testEstimates(), BetRampParams{
+		TableMin:        10,
+		TableMax:        50,
+		MaxSpread:       10,
+		Bankroll:        100000,
+		KellyFraction:   1,
+		SpotCorrelation: DefaultSpotCorrelation,
+	})
+	for tc, bid := range ramp.Spread {
+		if bid.Units > 5 {
+			t.Fatalf("TC %d bet %f units over the table max", tc, bid.Units)
+		}
+		if bid.Units*float32(bid.Hands) > 10 {
+			t.Fatalf("TC %d bet %+v over the max spread", tc, bid)
+		}
+	}
+	// capped by the table max, a second spot is the only way to get more money out
+	maxTC := 0
+	for tc := range ramp.Spread {
+		if tc > maxTC {
+			maxTC = tc
+		}
+	}
+	if bid := ramp.Spread[maxTC]; bid.Hands != 2 || bid.Units != 5 {
+		t.Fatalf("expected two spots of 5 units at the top of the ramp, got %+v", bid)
+	}
+}
+
+func TestBidspreadFormat(t *testing.T) {
+	spread := "-1:1;0:1;1:2;2:4;3:6:2"
+	parsed, err := ParseBidspread(spread)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed[3].Hands != 2 || parsed[3].Units != 6 {
+		t.Fatalf("incorrectly parsed TC 3, got %+v", parsed[3])
+	}
+	if formatted := FormatBidspread(parsed); formatted != spread {
+		t.Fatalf("spread should round trip, got %s", formatted)
+	}
+}
@@ -1,5 +1,13 @@
 package strategies
 
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
 type BidStrategy struct {
 	Hands int
 	Units float32
@@ -12,7 +20,7 @@ type Bidspread struct {
 }
 
 func NewBidspread(spread map[int]BidStrategy) *Bidspread {
-	maxTC := 0
+	maxTC := math.MinInt32
 	maxBet := BidStrategy{Hands: 1, Units: 1}
 	for tc, bid := range spread {
 		if tc >= maxTC {
@@ -27,6 +35,22 @@ func NewBidspread(spread map[int]BidStrategy) *Bidspread {
 	}
 }
 
+// NewKeyCountBidspread builds a running count spread for unbalanced counts. It
+// bets 1 unit below the key count and ramps linearly from 2 units at the key
+// count up to maxBet at the pivot
+func NewKeyCountBidspread(keyCount int, pivot int, maxBet BidStrategy) *Bidspread {
+	spread := map[int]BidStrategy{}
+	for rc := keyCount; rc < pivot; rc++ {
+		ramp := float64(rc-keyCount) / float64(pivot-keyCount)
+		units := 2 + math.Round(ramp*float64(maxBet.Units-2))
+		spread[rc] = BidStrategy{Hands: 1, Units: float32(units)}
+	}
+	spread[pivot] = maxBet
+	return NewBidspread(spread)
+}
+
+func (bs *Bidspread) Instance() Bidder { return bs }
+
 func (bs *Bidspread) Bid(trueCount int) BidStrategy {
 	if trueCount >= bs.maxCount {
 		return bs.maxBet
@@ -36,3 +60,56 @@ func (bs *Bidspread) Bid(trueCount int) BidStrategy {
 	}
 	return BidStrategy{Hands: 1, Units: 1}
 }
+
+// ParseBidspread reads a spread in the `tc:units[:hands];...` format used by the CLI
+func ParseBidspread(s string) (map[int]BidStrategy, error) {
+	created := map[int]BidStrategy{}
+	if s == "" {
+		return created, nil
+	}
+	for _, s := range strings.Split(s, ";") {
+		parts := strings.Split(s, ":")
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("invalid spread format for key %s", s)
+		}
+		tc, err := strconv.ParseInt(parts[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing %s as number", parts[0])
+		}
+		bid, err := strconv.ParseInt(parts[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing %s as number", parts[1])
+		}
+		hands := 1
+		if len(parts) == 3 {
+			parsedHands, err := strconv.ParseInt(parts[2], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("failed parsing %s as number", parts[2])
+			}
+			hands = int(parsedHands)
+		}
+
+		created[int(tc)] = BidStrategy{Hands: hands, Units: float32(bid)}
+	}
+	return created, nil
+}
+
+// FormatBidspread writes a spread in the format accepted by ParseBidspread
+func FormatBidspread(spread map[int]BidStrategy) string {
+	counts := make([]int, 0, len(spread))
+	for tc := range spread {
+		counts = append(counts, tc)
+	}
+	sort.Ints(counts)
+
+	s := make([]string, 0, len(counts))
+	for _, tc := range counts {
+		bid := spread[tc]
+		entry := fmt.Sprintf("%d:%d", tc, int(bid.Units))
+		if bid.Hands > 1 {
+			entry += fmt.Sprintf(":%d", bid.Hands)
+		}
+		s = append(s, entry)
+	}
+	return strings.Join(s, ";")
+}
@@ -0,0 +1,127 @@
+package strategies
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+)
+
+// CountSystem describes the card tags of a counting system. Tags are keyed by
+// card value, aces are 11
+type CountSystem struct {
+	Name     string
+	Tags     map[int]int
+	Balanced bool
+	// Red 7 counts sevens by color, RedSevenTag replaces the 7 tag for red sevens
+	SplitSevens bool
+	RedSevenTag int
+	// IRC is IRCPerDeck*decks + IRCOffset, zero for balanced counts
+	IRCPerDeck int
+	IRCOffset  int
+}
+
+var HiLo = CountSystem{
+	Name:     "HiLo",
+	Tags:     map[int]int{2: 1, 3: 1, 4: 1, 5: 1, 6: 1, 7: 0, 8: 0, 9: 0, 10: -1, 11: -1},
+	Balanced: true,
+}
+
+// Knockout, the IRC of 4 - 4*decks makes the pivot +4
+var KO = CountSystem{
+	Name:       "KO",
+	Tags:       map[int]int{2: 1, 3: 1, 4: 1, 5: 1, 6: 1, 7: 1, 8: 0, 9: 0, 10: -1, 11: -1},
+	IRCPerDeck: -4,
+	IRCOffset:  4,
+}
+
+// Red 7 is HiLo plus the red sevens, started from -2 per deck so the pivot is 0
+var Red7 = CountSystem{
+	Name:        "Red 7",
+	Tags:        map[int]int{2: 1, 3: 1, 4: 1, 5: 1, 6: 1, 7: 0, 8: 0, 9: 0, 10: -1, 11: -1},
+	SplitSevens: true,
+	RedSevenTag: 1,
+	IRCPerDeck:  -2,
+}
+
+func (cs CountSystem) Tag(c core.Card) int {
+	if cs.SplitSevens && c.Value == 7 && (c.Suit == core.SuitDiamonds || c.Suit == core.SuitHearts) {
+		return cs.RedSevenTag
+	}
+	return cs.Tags[c.Value]
+}
+
+// IRC is the running count a freshly shuffled shoe starts at
+func (cs CountSystem) IRC(decks int) int {
+	if cs.Balanced {
+		return 0
+	}
+	return cs.IRCPerDeck*decks + cs.IRCOffset
+}
+
+// deckTags is the sum of the tags over one deck of the composition
+func (cs CountSystem) deckTags(spec core.DeckComposition) float32 {
+	comp := spec.Composition(1)
+	sum := float32(0)
+	for value, n := range comp {
+		sum += float32(n * cs.Tags[value])
+	}
+	if cs.SplitSevens {
+		// half the sevens are red
+		sum += float32(comp[7]) / 2 * float32(cs.RedSevenTag-cs.Tags[7])
+	}
+	return sum
+}
+
+// Drift is the tags per deck the composition adds over a standard deck, the
+// count runs up by it for every deck dealt from a neutral shoe
+func (cs CountSystem) Drift(spec core.DeckComposition) float32 {
+	return cs.deckTags(spec) - cs.deckTags(core.StandardDeck)
+}
+
+// CountSystems are the built in systems by CLI name
+var CountSystems = map[string]CountSystem{
+	"hilo": HiLo,
+	"ko":   KO,
+	"red7": Red7,
+}
+
+// ParseCountTags reads a tag table in the `card:tag;...` format, cards are 2-9, T and A.
+// Cards left out are tagged 0
+func ParseCountTags(s string) (CountSystem, error) {
+	system := CountSystem{Name: "custom", Tags: map[int]int{}}
+	sum := 0
+	for _, entry := range strings.Split(s, ";") {
+		parts := strings.Split(entry, ":")
+		if len(parts) != 2 {
+			return CountSystem{}, fmt.Errorf("invalid tag format for entry %s", entry)
+		}
+		value := 0
+		switch strings.ToUpper(parts[0]) {
+		case "T", "10":
+			value = 10
+		case "A":
+			value = 11
+		default:
+			v, err := strconv.ParseInt(parts[0], 10, 32)
+			if err != nil || v < 2 || v > 9 {
+				return CountSystem{}, fmt.Errorf("invalid card %s", parts[0])
+			}
+			value = int(v)
+		}
+		tag, err := strconv.ParseInt(parts[1], 10, 32)
+		if err != nil {
+			return CountSystem{}, fmt.Errorf("failed parsing %s as number", parts[1])
+		}
+		system.Tags[value] = int(tag)
+	}
+	for value, tag := range system.Tags {
+		if value == 10 {
+			tag *= 4
+		}
+		sum += tag
+	}
+	system.Balanced = sum == 0
+	return system, nil
+}
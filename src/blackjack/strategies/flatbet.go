@@ -14,6 +14,8 @@ func (strat *FlatbetStrategy) Update(cards ...core.Card) {}
 
 func (strat *FlatbetStrategy) Shuffle() {}
 
-func (strat *FlatbetStrategy) Bid(d core.Deck) BidStrategy {
+func (strat *FlatbetStrategy) TrueCount(d core.Shoe) int { return 0 }
+
+func (strat *FlatbetStrategy) Bid(d core.Shoe) BidStrategy {
 	return BidStrategy{Hands: 1, Units: 1}
 }
@@ -4,18 +4,28 @@ import "github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
 
 type HighLowCountStrategy struct {
 	RunningCount int
-	betspred     Bidspread
+	Method       TrueCountMethod
+	bidder       Bidder
 	Updates      int
 	HighTC       float32
 	LowTC        float32
 	AggregatedTC float32
 	BidsByTC     map[int]int
+	AceSideCount *AceSideCount // nil when not side counting aces
+	drift        float32       // tags per deck dealt from a neutral shoe of the deck composition
+	noise        func() float32
+	estimateErr  float32 // the error drawn for the cards seen so far
+	estimated    bool
 }
 
 func InitHighLow(bs map[int]BidStrategy) *HighLowCountStrategy {
+	return InitHighLowWithBidder(NewBidspread(bs))
+}
+
+func InitHighLowWithBidder(bidder Bidder) *HighLowCountStrategy {
 	return &HighLowCountStrategy{
 		RunningCount: 0,
-		betspred:     *NewBidspread(bs),
+		bidder:       bidder,
 		BidsByTC:     map[int]int{},
 	}
 }
@@ -23,12 +33,55 @@ func InitHighLow(bs map[int]BidStrategy) *HighLowCountStrategy {
 func (strat *HighLowCountStrategy) Instance() TrackingStrategy {
 	return &HighLowCountStrategy{
 		RunningCount: 0,
-		betspred:     strat.betspred,
+		Method:       strat.Method,
+		bidder:       strat.bidder.Instance(),
 		BidsByTC:     map[int]int{},
+		AceSideCount: strat.AceSideCount.Instance(),
+	}
+}
+
+func (strat *HighLowCountStrategy) SetBankroll(bankroll float32) {
+	if b, ok := strat.bidder.(BankrollAware); ok {
+		b.SetBankroll(bankroll)
+	}
+}
+
+func (strat *HighLowCountStrategy) SizesByBankroll() bool {
+	b, ok := strat.bidder.(BankrollAware)
+	return ok && b.SizesByBankroll()
+}
+
+func (strat *HighLowCountStrategy) SetDeck(spec core.DeckComposition) {
+	strat.drift = HiLo.Drift(spec)
+	if strat.AceSideCount != nil {
+		strat.AceSideCount.SetDeck(spec)
+	}
+}
+
+func (strat *HighLowCountStrategy) Miscount(delta int) {
+	strat.RunningCount += delta
+}
+
+func (strat *HighLowCountStrategy) SetTrueCountNoise(noise func() float32) {
+	strat.noise = noise
+}
+
+// estimate adds the player's estimation error to the true count, if any. The
+// error is drawn once and held until more cards are seen, so the bet and the
+// plays made off the same cards agree
+func (strat *HighLowCountStrategy) estimate(tc float32) float32 {
+	if strat.noise == nil {
+		return tc
 	}
+	if !strat.estimated {
+		strat.estimateErr = strat.noise()
+		strat.estimated = true
+	}
+	return tc + strat.estimateErr
 }
 
 func (strat *HighLowCountStrategy) Update(cards ...core.Card) {
+	strat.estimated = false
 	for _, c := range cards {
 		strat.Updates++
 		switch c.Value {
@@ -38,24 +91,75 @@ func (strat *HighLowCountStrategy) Update(cards ...core.Card) {
 			strat.RunningCount--
 		default:
 		}
+		if strat.AceSideCount != nil {
+			strat.AceSideCount.Update(c)
+		}
 	}
 }
 
 func (strat *HighLowCountStrategy) Shuffle() {
 	strat.RunningCount = 0
+	strat.estimated = false
 	strat.HighTC = 0
 	strat.LowTC = 0
+	if strat.AceSideCount != nil {
+		strat.AceSideCount.Shuffle()
+	}
 }
 
-func (strat *HighLowCountStrategy) Bid(d core.Deck) BidStrategy {
-	est := d.EstimateRemaining()
-	tc := float32(strat.RunningCount) / est
+// runningCount takes out what a neutral shoe of the deck composition would
+// have counted to by now, leaving the count a standard deck would be at
+func (strat *HighLowCountStrategy) runningCount(d core.Shoe) float32 {
+	rc := float32(strat.RunningCount)
+	if strat.drift != 0 {
+		rc -= strat.drift * (d.Decks() - d.EstimateRemaining())
+	}
+	return rc
+}
+
+// PlayingTrueCount is the unadjusted true count used for playing decisions
+func (strat *HighLowCountStrategy) PlayingTrueCount(d core.Shoe) float32 {
+	return strat.estimate(strat.Method.TrueCount(strat.runningCount(d), d))
+}
+
+// InsuranceTrueCount is the playing true count with the aces taken out by the
+// ace side count, when there is one
+func (strat *HighLowCountStrategy) InsuranceTrueCount(d core.Shoe) float32 {
+	tc := strat.PlayingTrueCount(d)
+	if strat.AceSideCount != nil {
+		tc += strat.AceSideCount.InsuranceAdjustment(d)
+	}
+	return tc
+}
+
+// trueCount is the betting true count, including the ace side count adjustment
+// but without any estimation error
+func (strat *HighLowCountStrategy) trueCount(d core.Shoe) float32 {
+	rc := strat.runningCount(d)
+	if strat.AceSideCount != nil {
+		rc += strat.AceSideCount.BettingAdjustment(d)
+	}
+	return strat.Method.TrueCount(rc, d)
+}
+
+func (strat *HighLowCountStrategy) TrueCount(d core.Shoe) int {
+	return strat.Method.Convert(strat.estimate(strat.trueCount(d)))
+}
+
+func (strat *HighLowCountStrategy) Bid(d core.Shoe) BidStrategy {
+	exact := strat.trueCount(d)
+	tc := strat.estimate(exact)
 	if tc < strat.LowTC {
 		strat.LowTC = tc
 	} else if tc > strat.HighTC {
 		strat.HighTC = tc
 	}
 	strat.AggregatedTC += tc
-	strat.BidsByTC[int(tc)]++
-	return strat.betspred.Bid(int(tc))
+	count := strat.Method.Convert(tc)
+	if strat.AceSideCount != nil &&
+		strat.Method.Convert(exact) != strat.Method.Convert(strat.Method.TrueCount(strat.runningCount(d), d)) {
+		strat.AceSideCount.AdjustedBids++
+	}
+	strat.BidsByTC[count]++
+	return strat.bidder.Bid(count)
 }
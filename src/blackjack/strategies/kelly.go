@@ -0,0 +1,103 @@
+package strategies
+
+import "math"
+
+// Rough HiLo figures for a 6 deck game, the player is ~0.5% behind off the top
+// and gains ~0.5% per true count. Other games should Calibrate the bidder
+const (
+	DefaultBaseEdge    = -0.005
+	DefaultEdgePerTC   = 0.005
+	DefaultBetVariance = 1.3
+)
+
+// KellyBidder bets a fraction of the current bankroll proportional to the
+// estimated advantage at the count. All amounts are in units
+type KellyBidder struct {
+	Fraction  float32 // 1 for full kelly, .5 for half, .25 for quarter
+	BaseEdge  float32 // estimated advantage at TC 0
+	EdgePerTC float32
+	Variance  float32
+	MinBet    float32
+	MaxBet    float32 // 0 for no table max
+	ChipSize  float32 // bets are rounded down to a multiple of this
+	bankroll  float32
+}
+
+func NewKellyBidder(fraction float32, minBet float32, maxBet float32) *KellyBidder {
+	return &KellyBidder{
+		Fraction:  fraction,
+		BaseEdge:  DefaultBaseEdge,
+		EdgePerTC: DefaultEdgePerTC,
+		Variance:  DefaultBetVariance,
+		MinBet:    minBet,
+		MaxBet:    maxBet,
+		ChipSize:  1,
+	}
+}
+
+func (k *KellyBidder) Instance() Bidder {
+	created := *k
+	return &created
+}
+
+func (k *KellyBidder) SetBankroll(bankroll float32) {
+	k.bankroll = bankroll
+}
+
+func (k *KellyBidder) SizesByBankroll() bool {
+	return true
+}
+
+// Calibrate fits the edge model to per-count estimates from a flatbet sim of the
+// game. The edge is a least squares line through the EV at each count, weighted
+// by how often the count comes up, and the variance is the average of a round.
+// Counts seen too rarely to estimate are left out
+func (k *KellyBidder) Calibrate(estimates map[int]TCEstimate) {
+	weight, meanTC, meanEV, variance := 0.0, 0.0, 0.0, 0.0
+	for tc, est := range estimates {
+		if est.Frequency < minRampFrequency {
+			continue
+		}
+		weight += est.Frequency
+		meanTC += est.Frequency * float64(tc)
+		meanEV += est.Frequency * est.EV
+		variance += est.Frequency * est.Variance
+	}
+	if weight == 0 {
+		return
+	}
+	meanTC, meanEV, variance = meanTC/weight, meanEV/weight, variance/weight
+	covariance, spread := 0.0, 0.0
+	for tc, est := range estimates {
+		if est.Frequency < minRampFrequency {
+			continue
+		}
+		covariance += est.Frequency * (float64(tc) - meanTC) * (est.EV - meanEV)
+		spread += est.Frequency * (float64(tc) - meanTC) * (float64(tc) - meanTC)
+	}
+	if spread > 0 {
+		k.EdgePerTC = float32(covariance / spread)
+	}
+	k.BaseEdge = float32(meanEV) - k.EdgePerTC*float32(meanTC)
+	if variance > 0 {
+		k.Variance = float32(variance)
+	}
+}
+
+func (k *KellyBidder) Advantage(trueCount int) float32 {
+	return k.BaseEdge + k.EdgePerTC*float32(trueCount)
+}
+
+func (k *KellyBidder) Bid(trueCount int) BidStrategy {
+	bet := k.Fraction * k.bankroll * k.Advantage(trueCount) / k.Variance
+	if k.ChipSize > 0 {
+		bet = float32(math.Floor(float64(bet/k.ChipSize))) * k.ChipSize
+	}
+	if k.MaxBet > 0 && bet > k.MaxBet {
+		bet = k.MaxBet
+	}
+	if bet < k.MinBet {
+		bet = k.MinBet
+	}
+	return BidStrategy{Hands: 1, Units: bet}
+}
@@ -0,0 +1,57 @@
+package strategies
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKellyBidder(t *testing.T) {
+	kelly := NewKellyBidder(1, 1, 50)
+	kelly.SetBankroll(1000)
+
+	if bid := kelly.Bid(0); bid.Units != 1 {
+		t.Fatalf("should bet the minimum without an advantage, got %f", bid.Units)
+	}
+	// 1000 * (-0.005 + 0.005*3) / 1.3 = 7.69, rounded down to 7 chips
+	if bid := kelly.Bid(3); bid.Units != 7 {
+		t.Fatalf("expected a full kelly bet of 7 units at TC 3, got %f", bid.Units)
+	}
+	if bid := kelly.Bid(20); bid.Units != 50 {
+		t.Fatalf("bet should be capped at the table max, got %f", bid.Units)
+	}
+
+	half := NewKellyBidder(0.5, 1, 0)
+	half.ChipSize = 0.2
+	half.SetBankroll(1000)
+	// 1000 * 0.5 * 0.01 / 1.3 = 3.84, rounded down to 3.8
+	if bid := half.Bid(3); bid.Units < 3.79 || bid.Units > 3.81 {
+		t.Fatalf("expected a half kelly bet of 3.8 units at TC 3, got %f", bid.Units)
+	}
+}
+
+func TestKellyBidderCalibrate(t *testing.T) {
+	kelly := NewKellyBidder(1, 1, 0)
+	kelly.Calibrate(map[int]TCEstimate{
+		-1: {Frequency: 0.3, EV: -0.02, Variance: 1.2},
+		0:  {Frequency: 0.4, EV: -0.01, Variance: 1.2},
+		1:  {Frequency: 0.3, EV: 0, Variance: 1.4},
+		9:  {Frequency: 0.0001, EV: 1, Variance: 1}, // too rare to count
+	})
+	if math.Abs(float64(kelly.BaseEdge)+0.01) > 1e-6 || math.Abs(float64(kelly.EdgePerTC)-0.01) > 1e-6 {
+		t.Fatalf("expected -1%% at TC 0 and 1%% per TC, got %f and %f", kelly.BaseEdge, kelly.EdgePerTC)
+	}
+	if math.Abs(float64(kelly.Variance)-1.26) > 1e-6 {
+		t.Fatalf("expected the average variance of 1.26, got %f", kelly.Variance)
+	}
+}
+
+func TestKellyBidderInstance(t *testing.T) {
+	strat := InitHighLowWithBidder(NewKellyBidder(1, 1, 0))
+	a := strat.Instance().(*HighLowCountStrategy)
+	b := strat.Instance().(*HighLowCountStrategy)
+	a.SetBankroll(1000)
+	b.SetBankroll(100000)
+	if a.bidder.Bid(3).Units == b.bidder.Bid(3).Units {
+		t.Fatalf("instances should not share a bankroll")
+	}
+}
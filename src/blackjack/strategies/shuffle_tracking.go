@@ -0,0 +1,245 @@
+package strategies
+
+import (
+	"math/rand/v2"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+)
+
+// trackingSamples is how many modeled shuffles the segment map is averaged over
+const trackingSamples = 500
+
+// ShuffleTrackingStrategy keeps the HiLo count of every segment of the discards
+// and follows them through the casino's shuffle, betting on the count of the
+// cards about to be dealt rather than the whole of the shoe. Against a random
+// shuffle it's a plain HiLo count
+type ShuffleTrackingStrategy struct {
+	Method     TrueCountMethod
+	Segment    int // cards per tracked segment
+	Window     int // cards ahead the count is taken over
+	HiddenBurn int // cards burned unseen off the front of the shoe after each shuffle
+
+	RunningCount int
+	BidsByTC     map[int]int
+	decks        int
+	cards        int
+	perDeck      int     // cards in each deck
+	shoeTags     float64 // tags over the whole shoe, 0 for a standard deck
+	shuffler     core.Shuffler
+	bidder       Bidder
+	landing      [][]float64 // [new segment][old segment] share of the old segment's cards
+	seen         []int       // tags of the dealt cards by segment, in deal order
+	dealt        int         // cards seen since the shuffle
+	predicted    []float64   // expected tags per card of the shoe being dealt, 0 when unknown
+}
+
+// InitShuffleTracker builds a tracker for the shoe size and shuffle, the map of
+// where each segment lands is worked out up front off the modeled shuffle
+func InitShuffleTracker(shuffler core.Shuffler, decks int, segment int, window int, bidder Bidder) *ShuffleTrackingStrategy {
+	strat := &ShuffleTrackingStrategy{
+		Segment:  segment,
+		Window:   window,
+		BidsByTC: map[int]int{},
+		decks:    decks,
+		cards:    decks * core.DeckSize,
+		perDeck:  core.DeckSize,
+		shuffler: shuffler,
+		bidder:   bidder,
+	}
+	strat.mapSegments()
+	return strat
+}
+
+func (strat *ShuffleTrackingStrategy) mapSegments() {
+	strat.landing = nil
+	if strat.shuffler != nil {
+		strat.landing = segmentLanding(strat.shuffler, strat.cards, strat.Segment)
+	}
+	strat.seen = make([]int, strat.segments())
+}
+
+// SetDeck sizes the shoe off the deck composition, remapping the segments when
+// the number of cards changes
+func (strat *ShuffleTrackingStrategy) SetDeck(spec core.DeckComposition) {
+	strat.perDeck = spec.Size()
+	strat.shoeTags = float64(HiLo.deckTags(spec)) * float64(strat.decks)
+	if cards := strat.decks * strat.perDeck; cards != strat.cards {
+		strat.cards = cards
+		strat.mapSegments()
+	}
+}
+
+// segmentLanding averages where the cards of each segment end up over many shuffles
+func segmentLanding(shuffler core.Shuffler, cards int, segment int) [][]float64 {
+	segments := (cards + segment - 1) / segment
+	landing := make([][]float64, segments)
+	for i := range landing {
+		landing[i] = make([]float64, segments)
+	}
+	source := rand.New(rand.NewPCG(uint64(cards), uint64(segment)))
+	order := make([]int, cards)
+	for sample := 0; sample < trackingSamples; sample++ {
+		for i := range order {
+			order[i] = i
+		}
+		shuffler.Shuffle(order, source)
+		for position, from := range order {
+			landing[position/segment][from/segment]++
+		}
+	}
+	for _, row := range landing {
+		for from := range row {
+			row[from] /= float64(trackingSamples * segmentSize(from, cards, segment))
+		}
+	}
+	return landing
+}
+
+func segmentSize(idx int, cards int, segment int) int {
+	if remaining := cards - idx*segment; remaining < segment {
+		return remaining
+	}
+	return segment
+}
+
+func (strat *ShuffleTrackingStrategy) segments() int {
+	return (strat.cards + strat.Segment - 1) / strat.Segment
+}
+
+func (strat *ShuffleTrackingStrategy) Instance() TrackingStrategy {
+	return &ShuffleTrackingStrategy{
+		Method:     strat.Method,
+		Segment:    strat.Segment,
+		Window:     strat.Window,
+		HiddenBurn: strat.HiddenBurn,
+		BidsByTC:   map[int]int{},
+		decks:      strat.decks,
+		cards:      strat.cards,
+		perDeck:    strat.perDeck,
+		shoeTags:   strat.shoeTags,
+		shuffler:   strat.shuffler,
+		bidder:     strat.bidder.Instance(),
+		landing:    strat.landing,
+		seen:       make([]int, len(strat.seen)),
+	}
+}
+
+// position is where in the shoe the next card comes from, behind the hidden burns
+func (strat *ShuffleTrackingStrategy) position() int {
+	return strat.HiddenBurn + strat.dealt
+}
+
+func (strat *ShuffleTrackingStrategy) SetBankroll(bankroll float32) {
+	if b, ok := strat.bidder.(BankrollAware); ok {
+		b.SetBankroll(bankroll)
+	}
+}
+
+func (strat *ShuffleTrackingStrategy) SizesByBankroll() bool {
+	b, ok := strat.bidder.(BankrollAware)
+	return ok && b.SizesByBankroll()
+}
+
+func (strat *ShuffleTrackingStrategy) Miscount(delta int) {
+	strat.RunningCount += delta
+}
+
+func (strat *ShuffleTrackingStrategy) Update(cards ...core.Card) {
+	for _, c := range cards {
+		tag := 0
+		switch c.Value {
+		case 2, 3, 4, 5, 6:
+			tag = 1
+		case 10, 11:
+			tag = -1
+		}
+		strat.RunningCount += tag
+		if segment := strat.position() / strat.Segment; segment < len(strat.seen) {
+			strat.seen[segment] += tag
+		}
+		strat.dealt++
+	}
+}
+
+// Shuffle follows the discards through the shuffle. The unplayed cards go in
+// last and the hidden burns first, holding the rest of the count spread evenly
+// between them
+func (strat *ShuffleTrackingStrategy) Shuffle() {
+	if strat.landing != nil && strat.dealt > 0 {
+		pile := make([]float64, len(strat.seen))
+		unplayed := strat.cards - strat.dealt
+		for i := range pile {
+			size := segmentSize(i, strat.cards, strat.Segment)
+			// the seen cards of the segment, between the hidden burns and the next card
+			first, last := i*strat.Segment, i*strat.Segment+size
+			if first < strat.HiddenBurn {
+				first = strat.HiddenBurn
+			}
+			if last > strat.position() {
+				last = strat.position()
+			}
+			played := last - first
+			if played < 0 {
+				played = 0
+			}
+			pile[i] = float64(strat.seen[i])
+			if unplayed > 0 {
+				pile[i] += (strat.shoeTags - float64(strat.RunningCount)) * float64(size-played) / float64(unplayed)
+			}
+		}
+		strat.predicted = make([]float64, strat.cards)
+		for position := range strat.predicted {
+			segment := position / strat.Segment
+			tags := float64(0)
+			for from, share := range strat.landing[segment] {
+				tags += share * pile[from]
+			}
+			strat.predicted[position] = tags / float64(segmentSize(segment, strat.cards, strat.Segment))
+		}
+	}
+	for i := range strat.seen {
+		strat.seen[i] = 0
+	}
+	strat.RunningCount = 0
+	strat.dealt = 0
+}
+
+// trueCount is the count per deck of the next Window cards. The tags the shuffle
+// predicts for them are corrected by the difference between what's left of the
+// count and what the shuffle predicted for the unseen cards, the rest of the
+// shoe and the hidden burns
+func (strat *ShuffleTrackingStrategy) trueCount(d core.Shoe) float32 {
+	next := strat.position()
+	remaining := strat.cards - next
+	if remaining <= 0 || strat.predicted == nil {
+		return strat.Method.TrueCount(float32(strat.RunningCount), d)
+	}
+	window := strat.Window
+	if window > remaining {
+		window = remaining
+	}
+	ahead, unseen := float64(0), float64(0)
+	for position := 0; position < strat.cards; position++ {
+		if position >= strat.HiddenBurn && position < next {
+			continue
+		}
+		if position >= next && position < next+window {
+			ahead += strat.predicted[position]
+		}
+		unseen += strat.predicted[position]
+	}
+	ahead += (strat.shoeTags - float64(strat.RunningCount) - unseen) * float64(window) / float64(strat.cards-strat.dealt)
+	// a deck other than a standard one isn't neutral at zero
+	ahead -= strat.shoeTags * float64(window) / float64(strat.cards)
+	return float32(-ahead * float64(strat.perDeck) / float64(window))
+}
+
+func (strat *ShuffleTrackingStrategy) TrueCount(d core.Shoe) int {
+	return strat.Method.Convert(strat.trueCount(d))
+}
+
+func (strat *ShuffleTrackingStrategy) Bid(d core.Shoe) BidStrategy {
+	count := strat.TrueCount(d)
+	strat.BidsByTC[count]++
+	return strat.bidder.Bid(count)
+}
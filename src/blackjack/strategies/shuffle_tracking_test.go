@@ -0,0 +1,60 @@
+package strategies
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+)
+
+func TestShuffleTrackerFollowsSlugs(t *testing.T) {
+	deck := core.GenerateShoe(1)
+	// a shuffle that leaves the cards where they were, the low cards dealt first come out first again
+	tracker := InitShuffleTracker(core.Procedure{}, 1, 26, 26, NewBidspread(map[int]BidStrategy{}))
+	random := InitShuffleTracker(core.RandomShuffle{}, 1, 26, 26, NewBidspread(map[int]BidStrategy{}))
+	for _, strat := range []*ShuffleTrackingStrategy{tracker, random} {
+		for value := 2; value <= 6; value++ {
+			for i := 0; i < core.Suits; i++ {
+				strat.Update(core.Card{Value: value})
+			}
+		}
+		strat.Shuffle()
+	}
+	if tc := tracker.TrueCount(deck); tc > -10 {
+		t.Fatalf("the low card slug should be coming out first, got TC %d", tc)
+	}
+	if tc := random.TrueCount(deck); tc < -2 || tc > 2 {
+		t.Fatalf("a random shuffle can't be tracked, got TC %d", tc)
+	}
+}
+
+// reverseShuffle turns the discards over, the first card dealt comes out last
+type reverseShuffle struct{}
+
+func (reverseShuffle) Shuffle(order []int, source *rand.Rand) {
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+}
+
+func (reverseShuffle) ToString() string {
+	return "reverse"
+}
+
+func TestShuffleTrackerHiddenBurn(t *testing.T) {
+	deck := core.GenerateShoe(1)
+	tracker := InitShuffleTracker(reverseShuffle{}, 1, 13, 13, NewBidspread(map[int]BidStrategy{}))
+	tracker.HiddenBurn = 13
+	// the low cards come out behind the burn, 13th to 25th, and turned over
+	// they land 26th to 38th
+	for i := 0; i < 13; i++ {
+		tracker.Update(core.Card{Value: 2})
+	}
+	tracker.Shuffle()
+	for i := 0; i < 13; i++ {
+		tracker.Update(core.Card{Value: 8})
+	}
+	if tc := tracker.TrueCount(deck); tc > -10 {
+		t.Fatalf("the low card slug should be next after the burn and 13 cards, got TC %d", tc)
+	}
+}
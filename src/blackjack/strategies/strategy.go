@@ -5,6 +5,39 @@ import "github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
 type TrackingStrategy interface {
 	Instance() TrackingStrategy
 	Update(cards ...core.Card)
-	Bid(d core.Deck) BidStrategy
+	Bid(d core.Shoe) BidStrategy
+	// TrueCount returns the count bucket the next bid would be placed at
+	TrueCount(d core.Shoe) int
 	Shuffle()
 }
+
+// Bidder sizes a bet from the count
+type Bidder interface {
+	Instance() Bidder
+	Bid(trueCount int) BidStrategy
+}
+
+// BankrollAware is implemented by strategies that can size bets off the current
+// bankroll, SizesByBankroll is false while they bet a fixed spread
+type BankrollAware interface {
+	SetBankroll(bankroll float32)
+	SizesByBankroll() bool
+}
+
+// Miscountable is implemented by strategies that keep a running count the
+// player can get wrong
+type Miscountable interface {
+	Miscount(delta int)
+}
+
+// TrueCountEstimator is implemented by strategies that estimate a true count,
+// the noise is added to every estimate
+type TrueCountEstimator interface {
+	SetTrueCountNoise(noise func() float32)
+}
+
+// DeckAware is implemented by strategies that need the composition of the
+// decks in the shoe, counts built for a standard deck are recentred off it
+type DeckAware interface {
+	SetDeck(spec core.DeckComposition)
+}
@@ -0,0 +1,123 @@
+package strategies
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+)
+
+// DeckEstimation is how finely the player estimates the decks left in the shoe
+type DeckEstimation int
+
+const (
+	DeckEstimationExact DeckEstimation = iota
+	DeckEstimationQuarter
+	DeckEstimationHalf
+	DeckEstimationFull
+)
+
+// TrueCountRounding is how the player turns the true count into a whole number
+type TrueCountRounding int
+
+const (
+	TrueCountTruncate TrueCountRounding = iota
+	TrueCountFloor
+	TrueCountRound
+)
+
+func (e DeckEstimation) ToString() string {
+	switch e {
+	case DeckEstimationExact:
+		return `exact`
+	case DeckEstimationQuarter:
+		return `quarter`
+	case DeckEstimationHalf:
+		return `half`
+	case DeckEstimationFull:
+		return `full`
+	}
+	return `unknown`
+}
+
+func ParseDeckEstimation(s string) (DeckEstimation, error) {
+	for e := DeckEstimationExact; e <= DeckEstimationFull; e++ {
+		if e.ToString() == s {
+			return e, nil
+		}
+	}
+	return DeckEstimationExact, fmt.Errorf("unknown deck estimation %s", s)
+}
+
+func (r TrueCountRounding) ToString() string {
+	switch r {
+	case TrueCountTruncate:
+		return `truncate`
+	case TrueCountFloor:
+		return `floor`
+	case TrueCountRound:
+		return `round`
+	}
+	return `unknown`
+}
+
+func ParseTrueCountRounding(s string) (TrueCountRounding, error) {
+	for r := TrueCountTruncate; r <= TrueCountRound; r++ {
+		if r.ToString() == s {
+			return r, nil
+		}
+	}
+	return TrueCountTruncate, fmt.Errorf("unknown true count rounding %s", s)
+}
+
+// TrueCountMethod converts a running count to a true count the way a player at
+// the table would. The zero value is an exact estimate, truncated
+type TrueCountMethod struct {
+	Estimation DeckEstimation
+	Rounding   TrueCountRounding
+}
+
+func (m TrueCountMethod) step() float32 {
+	switch m.Estimation {
+	case DeckEstimationQuarter:
+		return 0.25
+	case DeckEstimationHalf:
+		return 0.5
+	case DeckEstimationFull:
+		return 1
+	}
+	return 0
+}
+
+// DecksRemaining estimates the decks left in the shoe, never less than the
+// smallest fraction of a deck the player can estimate
+func (m TrueCountMethod) DecksRemaining(d core.Shoe) float32 {
+	exact := d.EstimateRemaining()
+	step := m.step()
+	if step == 0 {
+		return exact
+	}
+	est := float32(math.Round(float64(exact/step))) * step
+	if est < step {
+		return step
+	}
+	return est
+}
+
+func (m TrueCountMethod) TrueCount(runningCount float32, d core.Shoe) float32 {
+	return runningCount / m.DecksRemaining(d)
+}
+
+func (m TrueCountMethod) Convert(tc float32) int {
+	switch m.Rounding {
+	case TrueCountFloor:
+		return int(math.Floor(float64(tc)))
+	case TrueCountRound:
+		return int(math.Round(float64(tc)))
+	}
+	return int(tc)
+}
+
+func (m TrueCountMethod) ToString() string {
+	return fmt.Sprintf("%s deck estimation, %s", m.Estimation.ToString(), m.Rounding.ToString())
+}
@@ -0,0 +1,61 @@
+package strategies
+
+import (
+	"testing"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+)
+
+func dealTo(remaining int) *core.Deck {
+	d := core.GenerateShoe(6)
+	for d.Remaining() > remaining {
+		d.Deal()
+	}
+	return d
+}
+
+func TestDeckEstimation(t *testing.T) {
+	tests := []struct {
+		Remaining int
+		Method    DeckEstimation
+		Expected  float32
+	}{
+		{Remaining: 182, Method: DeckEstimationExact, Expected: 3.5},
+		{Remaining: 182, Method: DeckEstimationHalf, Expected: 3.5},
+		{Remaining: 182, Method: DeckEstimationFull, Expected: 4},
+		{Remaining: 170, Method: DeckEstimationQuarter, Expected: 3.25},
+		{Remaining: 170, Method: DeckEstimationHalf, Expected: 3.5},
+		{Remaining: 170, Method: DeckEstimationFull, Expected: 3},
+		{Remaining: 10, Method: DeckEstimationHalf, Expected: 0.5},
+		{Remaining: 10, Method: DeckEstimationFull, Expected: 1},
+	}
+	for _, test := range tests {
+		method := TrueCountMethod{Estimation: test.Method}
+		if est := method.DecksRemaining(dealTo(test.Remaining)); est != test.Expected {
+			t.Fatalf("%s estimation of %d cards should be %f decks, got %f",
+				test.Method.ToString(), test.Remaining, test.Expected, est)
+		}
+	}
+}
+
+func TestTrueCountRounding(t *testing.T) {
+	tests := []struct {
+		TC       float32
+		Rounding TrueCountRounding
+		Expected int
+	}{
+		{TC: 1.5, Rounding: TrueCountTruncate, Expected: 1},
+		{TC: 1.5, Rounding: TrueCountFloor, Expected: 1},
+		{TC: 1.5, Rounding: TrueCountRound, Expected: 2},
+		{TC: -1.5, Rounding: TrueCountTruncate, Expected: -1},
+		{TC: -1.5, Rounding: TrueCountFloor, Expected: -2},
+		{TC: -1.4, Rounding: TrueCountRound, Expected: -1},
+		{TC: -0.5, Rounding: TrueCountFloor, Expected: -1},
+	}
+	for _, test := range tests {
+		method := TrueCountMethod{Rounding: test.Rounding}
+		if tc := method.Convert(test.TC); tc != test.Expected {
+			t.Fatalf("%s of %f should be %d, got %d", test.Rounding.ToString(), test.TC, test.Expected, tc)
+		}
+	}
+}
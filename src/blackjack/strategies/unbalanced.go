@@ -0,0 +1,77 @@
+package strategies
+
+import (
+	"math"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+)
+
+// UnbalancedCountStrategy counts with an unbalanced system like KO or Red 7.
+// The IRC offsets the count so bets are placed straight off the running count
+type UnbalancedCountStrategy struct {
+	System       CountSystem
+	Decks        int
+	RunningCount int
+	bidder       Bidder
+	BidsByRC     map[int]int
+	drift        float32 // tags per deck dealt from a neutral shoe over a standard deck
+}
+
+func InitUnbalanced(system CountSystem, decks int, bidder Bidder) *UnbalancedCountStrategy {
+	return &UnbalancedCountStrategy{
+		System:       system,
+		Decks:        decks,
+		RunningCount: system.IRC(decks),
+		bidder:       bidder,
+		BidsByRC:     map[int]int{},
+	}
+}
+
+func (strat *UnbalancedCountStrategy) Instance() TrackingStrategy {
+	return InitUnbalanced(strat.System, strat.Decks, strat.bidder.Instance())
+}
+
+func (strat *UnbalancedCountStrategy) SetBankroll(bankroll float32) {
+	if b, ok := strat.bidder.(BankrollAware); ok {
+		b.SetBankroll(bankroll)
+	}
+}
+
+func (strat *UnbalancedCountStrategy) SizesByBankroll() bool {
+	b, ok := strat.bidder.(BankrollAware)
+	return ok && b.SizesByBankroll()
+}
+
+func (strat *UnbalancedCountStrategy) SetDeck(spec core.DeckComposition) {
+	strat.drift = strat.System.Drift(spec)
+}
+
+func (strat *UnbalancedCountStrategy) Miscount(delta int) {
+	strat.RunningCount += delta
+}
+
+func (strat *UnbalancedCountStrategy) Update(cards ...core.Card) {
+	for _, c := range cards {
+		strat.RunningCount += strat.System.Tag(c)
+	}
+}
+
+func (strat *UnbalancedCountStrategy) Shuffle() {
+	strat.RunningCount = strat.System.IRC(strat.Decks)
+}
+
+// TrueCount is the running count, unbalanced systems bet without converting.
+// Decks other than a standard one have the drift of the cards dealt taken out
+// so the key count and pivot stay where they were
+func (strat *UnbalancedCountStrategy) TrueCount(d core.Shoe) int {
+	if strat.drift == 0 {
+		return strat.RunningCount
+	}
+	return strat.RunningCount - int(math.Round(float64(strat.drift*(d.Decks()-d.EstimateRemaining()))))
+}
+
+func (strat *UnbalancedCountStrategy) Bid(d core.Shoe) BidStrategy {
+	count := strat.TrueCount(d)
+	strat.BidsByRC[count]++
+	return strat.bidder.Bid(count)
+}
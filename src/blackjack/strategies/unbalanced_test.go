@@ -0,0 +1,116 @@
+package strategies
+
+import (
+	"testing"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+)
+
+func TestUnbalancedIRC(t *testing.T) {
+	if irc := KO.IRC(6); irc != -20 {
+		t.Fatalf("6 deck KO should start at -20, got %d", irc)
+	}
+	if irc := Red7.IRC(6); irc != -12 {
+		t.Fatalf("6 deck Red 7 should start at -12, got %d", irc)
+	}
+	if irc := HiLo.IRC(6); irc != 0 {
+		t.Fatalf("balanced counts should start at 0, got %d", irc)
+	}
+
+	tests := []struct {
+		System   CountSystem
+		Expected int
+	}{
+		{System: KO, Expected: 4},   // +4 a deck from -20
+		{System: Red7, Expected: 0}, // +2 a deck from -12
+		{System: HiLo, Expected: 0},
+	}
+	for _, test := range tests {
+		strat := InitUnbalanced(test.System, 6, NewBidspread(map[int]BidStrategy{}))
+		d := core.GenerateShoe(6)
+		for d.Remaining() > 0 {
+			strat.Update(d.Deal())
+		}
+		if strat.RunningCount != test.Expected {
+			t.Fatalf("%s should end the shoe at %d, got %d", test.System.Name, test.Expected, strat.RunningCount)
+		}
+		strat.Shuffle()
+		if strat.RunningCount != test.System.IRC(6) {
+			t.Fatalf("%s should reset to the IRC on shuffle", test.System.Name)
+		}
+	}
+}
+
+func TestKeyCountBidspread(t *testing.T) {
+	strat := InitUnbalanced(KO, 6, NewKeyCountBidspread(-4, 4, BidStrategy{Hands: 1, Units: 10}))
+	tests := []struct {
+		RC       int
+		Expected float32
+	}{
+		{RC: -20, Expected: 1},
+		{RC: -5, Expected: 1},
+		{RC: -4, Expected: 2}, // key count
+		{RC: 0, Expected: 6},
+		{RC: 4, Expected: 10}, // pivot
+		{RC: 9, Expected: 10},
+	}
+	for _, test := range tests {
+		strat.RunningCount = test.RC
+		if bid := strat.Bid(&core.Deck{}); bid.Units != test.Expected {
+			t.Fatalf("RC %d should bet %f units, got %f", test.RC, test.Expected, bid.Units)
+		}
+	}
+}
+
+func TestParseCountTags(t *testing.T) {
+	system, err := ParseCountTags("2:1;3:1;4:1;5:1;6:1;T:-1;A:-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for value, tag := range HiLo.Tags {
+		if system.Tags[value] != tag {
+			t.Fatalf("expected tag %d for %d, got %d", tag, value, system.Tags[value])
+		}
+	}
+	if !system.Balanced {
+		t.Fatalf("hilo tags should be balanced")
+	}
+	if system, _ := ParseCountTags("2:1;3:1;4:1;5:1;6:1;7:1;T:-1;A:-1"); system.Balanced {
+		t.Fatalf("ko tags should be unbalanced")
+	}
+	if _, err := ParseCountTags("1:1"); err == nil {
+		t.Fatalf("expected an error for an invalid card")
+	}
+}
+
+func TestCountDrift(t *testing.T) {
+	// four tens come out of every spanish deck, so HiLo and KO pick up 4 a deck
+	if drift := HiLo.Drift(core.SpanishDeck); drift != 4 {
+		t.Fatalf("HiLo should drift 4 a spanish deck, got %f", drift)
+	}
+	if drift := KO.Drift(core.SpanishDeck); drift != 4 {
+		t.Fatalf("KO should drift 4 a spanish deck, got %f", drift)
+	}
+	if drift := Red7.Drift(core.StandardDeck.Without("7")); drift != -2 {
+		t.Fatalf("Red 7 should lose the red sevens, got %f", drift)
+	}
+
+	// a whole neutral spanish shoe leaves the counts where a standard one would
+	hl := InitHighLow(map[int]BidStrategy{})
+	hl.SetDeck(core.SpanishDeck)
+	ko := InitUnbalanced(KO, 6, NewBidspread(map[int]BidStrategy{}))
+	ko.SetDeck(core.SpanishDeck)
+	d := core.GenerateShoeOf(6, core.SpanishDeck)
+	for d.Remaining() > 48 {
+		c := d.Deal()
+		hl.Update(c)
+		ko.Update(c)
+	}
+	if tc := hl.TrueCount(d); tc != 0 {
+		t.Fatalf("neutral spanish shoe should be at TC 0, got %d", tc)
+	}
+	// -20 plus 4 for each of the 5 decks dealt, as in a standard shoe
+	if rc := ko.TrueCount(d); rc != 0 {
+		t.Fatalf("KO should be at 0 five decks into the spanish shoe, got %d", rc)
+	}
+}
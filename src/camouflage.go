@@ -0,0 +1,80 @@
+package blackjack
+
+import "math/rand/v2"
+
+// Camouflage holds bets to patterns that look less like counting. Ratios and
+// rates of 0 turn the rule off
+type Camouflage struct {
+	MaxIncreaseRatio float32 // a bet can be at most this times the last one
+	ParlayOnly       bool    // only raise after a win, by at most the winnings
+	MaxDecreaseRatio float32 // after a loss a bet can't drop below the last one over this
+	OffCountRate     float64 // chance of a random bet regardless of the count
+
+	Adjusted int // bets changed by the rules
+	lastBet  float32
+	lastAV   float32
+	largest  float32
+	source   *rand.Rand
+}
+
+func NewCamouflage() *Camouflage {
+	return &Camouflage{source: rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))}
+}
+
+// Enabled is true when any of the rules are on
+func (c *Camouflage) Enabled() bool {
+	return c.MaxIncreaseRatio > 0 || c.ParlayOnly || c.MaxDecreaseRatio > 0 || c.OffCountRate > 0
+}
+
+// Instance copies the rules with fresh state, seeded when seed isn't 0
+func (c *Camouflage) Instance(seed uint64) *Camouflage {
+	if c == nil {
+		return nil
+	}
+	created := *c
+	created.Adjusted, created.lastBet, created.lastAV, created.largest = 0, 0, 0, 0
+	created.source = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	if seed != 0 {
+		created.source = rand.New(rand.NewPCG(^seed, seed))
+	}
+	return &created
+}
+
+// Adjust turns the bid in units into the bet the rules allow
+func (c *Camouflage) Adjust(units float32) float32 {
+	bet := units
+	if c.OffCountRate > 0 && c.largest > 1 && c.source.Float64() < c.OffCountRate {
+		// anything from a unit up to the biggest bet made so far
+		bet = 1 + float32(c.source.IntN(int(c.largest)))
+	}
+	if c.lastBet > 0 {
+		if c.ParlayOnly && bet > c.lastBet {
+			limit := c.lastBet
+			if c.lastAV > 0 {
+				limit += c.lastAV
+			}
+			if bet > limit {
+				bet = limit
+			}
+		}
+		if c.MaxIncreaseRatio > 0 && bet > c.lastBet*c.MaxIncreaseRatio {
+			bet = c.lastBet * c.MaxIncreaseRatio
+		}
+		if c.MaxDecreaseRatio > 0 && c.lastAV < 0 && bet < c.lastBet/c.MaxDecreaseRatio {
+			bet = c.lastBet / c.MaxDecreaseRatio
+		}
+	}
+	if bet != units {
+		c.Adjusted++
+	}
+	c.lastBet = bet
+	if bet > c.largest {
+		c.largest = bet
+	}
+	return bet
+}
+
+// Settle records the outcome of the last bet for the next one
+func (c *Camouflage) Settle(av float32) {
+	c.lastAV = av
+}
@@ -0,0 +1,53 @@
+package blackjack
+
+import "testing"
+
+func TestCamouflage(t *testing.T) {
+	raise := NewCamouflage()
+	raise.MaxIncreaseRatio = 2
+	if bet := raise.Adjust(1); bet != 1 {
+		t.Fatalf("first bet should be left alone, got %f", bet)
+	}
+	if bet := raise.Adjust(8); bet != 2 {
+		t.Fatalf("expected the raise held to 2x, got %f", bet)
+	}
+	if bet := raise.Adjust(8); bet != 4 {
+		t.Fatalf("expected the raise held to 2x, got %f", bet)
+	}
+
+	parlay := NewCamouflage()
+	parlay.ParlayOnly = true
+	parlay.Adjust(2)
+	parlay.Settle(-2)
+	if bet := parlay.Adjust(8); bet != 2 {
+		t.Fatalf("can't raise after a loss, got %f", bet)
+	}
+	parlay.Settle(2)
+	if bet := parlay.Adjust(8); bet != 4 {
+		t.Fatalf("expected to parlay the win to 4, got %f", bet)
+	}
+
+	drop := NewCamouflage()
+	drop.MaxDecreaseRatio = 2
+	drop.Adjust(8)
+	drop.Settle(-8)
+	if bet := drop.Adjust(1); bet != 4 {
+		t.Fatalf("expected the drop after a loss held to half, got %f", bet)
+	}
+	drop.Settle(4)
+	if bet := drop.Adjust(1); bet != 1 {
+		t.Fatalf("drops after a win aren't limited, got %f", bet)
+	}
+	if drop.Adjusted != 1 {
+		t.Fatalf("expected 1 adjusted bet, got %d", drop.Adjusted)
+	}
+
+	offCount := NewCamouflage()
+	offCount.OffCountRate = 1
+	offCount.Adjust(6)
+	for i := 0; i < 100; i++ {
+		if bet := offCount.Adjust(1); bet < 1 || bet > 6 {
+			t.Fatalf("off count bets should be between 1 and the largest bet, got %f", bet)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package blackjack
+
+import (
+	"math"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/strategies"
+)
+
+// card values of the 13 ranks, 2 through A
+var rankValues = []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 10, 10, 10, 11}
+
+type PlayCorrelation struct {
+	Play        Play
+	Correlation float64
+}
+
+type CountAnalysis struct {
+	System               string
+	BettingCorrelation   float64
+	PlayingEfficiency    float64 // mean strength of the correlation over the index plays
+	InsuranceCorrelation float64
+	Plays                []PlayCorrelation
+}
+
+// rankTags lays the system's tags out by rank, red/black sevens are averaged
+func rankTags(system strategies.CountSystem) []float64 {
+	tags := make([]float64, 0, len(rankValues))
+	for _, value := range rankValues {
+		tag := float64(system.Tags[value])
+		if value == 7 && system.SplitSevens {
+			tag = (tag + float64(system.RedSevenTag)) / 2
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func correlateByRank(tags []float64, effects EffectsOfRemoval) float64 {
+	byRank := make([]float64, 0, len(rankValues))
+	for _, value := range rankValues {
+		byRank = append(byRank, effects[value])
+	}
+	return correlation(tags, byRank)
+}
+
+// correlation is the pearson correlation of the two series
+func correlation(a []float64, b []float64) float64 {
+	meanA, meanB := float64(0), float64(0)
+	for i := range a {
+		meanA += a[i]
+		meanB += b[i]
+	}
+	meanA /= float64(len(a))
+	meanB /= float64(len(b))
+
+	cov, varA, varB := float64(0), float64(0), float64(0)
+	for i := range a {
+		cov += (a[i] - meanA) * (b[i] - meanB)
+		varA += (a[i] - meanA) * (a[i] - meanA)
+		varB += (b[i] - meanB) * (b[i] - meanB)
+	}
+	if varA == 0 || varB == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varA*varB)
+}
+
+// AnalyzeCount measures how well a count system's tags track the effects of
+// removal of each card under the rules, for betting, playing and insurance
+func AnalyzeCount(rules *BlackjackGameRules, system strategies.CountSystem, decks int) CountAnalysis {
+	comp := rules.deck().Composition(decks)
+	tags := rankTags(system)
+	analysis := CountAnalysis{
+		System:             system.Name,
+		BettingCorrelation: correlateByRank(tags, rules.EffectsOfRemoval(comp)),
+	}
+
+	plays := 0
+	for _, play := range IndexPlays {
+		corr := correlateByRank(tags, rules.PlayEffectsOfRemoval(play, comp))
+		analysis.Plays = append(analysis.Plays, PlayCorrelation{Play: play, Correlation: corr})
+		if play.Insurance {
+			analysis.InsuranceCorrelation = corr
+			continue
+		}
+		analysis.PlayingEfficiency += math.Abs(corr)
+		plays++
+	}
+	analysis.PlayingEfficiency /= float64(plays)
+	return analysis
+}
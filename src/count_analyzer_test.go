@@ -0,0 +1,60 @@
+package blackjack
+
+import (
+	"testing"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/strategies"
+)
+
+func TestExpectedValue(t *testing.T) {
+	// published 6 deck S17 DAS figures are around -0.4%
+	ev := MakeTestRules().SetDealerHitsSoft17(false).ExpectedValue(core.NewComposition(6))
+	if ev < -0.006 || ev > -0.002 {
+		t.Fatalf("expected 6 deck S17 EV around -0.4%%, got %f", ev)
+	}
+}
+
+func TestAnalyzeCount(t *testing.T) {
+	analysis := AnalyzeCount(MakeTestRules(), strategies.HiLo, 1)
+	// published HiLo figures are BC 0.97, IC 0.76
+	if analysis.BettingCorrelation < 0.95 || analysis.BettingCorrelation > 0.99 {
+		t.Fatalf("expected HiLo betting correlation around 0.97, got %f", analysis.BettingCorrelation)
+	}
+	if analysis.InsuranceCorrelation < 0.72 || analysis.InsuranceCorrelation > 0.80 {
+		t.Fatalf("expected HiLo insurance correlation around 0.76, got %f", analysis.InsuranceCorrelation)
+	}
+	if analysis.PlayingEfficiency <= 0 || analysis.PlayingEfficiency >= 1 {
+		t.Fatalf("playing efficiency out of range, got %f", analysis.PlayingEfficiency)
+	}
+	if len(analysis.Plays) != len(IndexPlays) {
+		t.Fatalf("expected a correlation for each play")
+	}
+}
+
+func TestEffectsOfRemoval(t *testing.T) {
+	eor := MakeTestRules().EffectsOfRemoval(core.NewComposition(1))
+	// small cards help the player, tens and aces hurt
+	for value := 2; value <= 6; value++ {
+		if eor[value] <= 0 {
+			t.Fatalf("removing a %d should help the player, got %f", value, eor[value])
+		}
+	}
+	if eor[10] >= 0 || eor[11] >= 0 {
+		t.Fatalf("removing tens and aces should hurt the player, got %f %f", eor[10], eor[11])
+	}
+
+	insurance, err := FindPlay("insurance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// one deck with the ace out, 16 tens in 51 cards
+	insuranceEOR := MakeTestRules().PlayEffectsOfRemoval(insurance, core.NewComposition(1))
+	expected := (3*16.0/50 - 1) - (3*16.0/51 - 1)
+	if diff := insuranceEOR[2] - expected; diff > 1e-9 || diff < -1e-9 {
+		t.Fatalf("expected insurance EOR of %f for a 2, got %f", expected, insuranceEOR[2])
+	}
+	if insuranceEOR[10] >= 0 {
+		t.Fatalf("removing a ten should hurt insurance, got %f", insuranceEOR[10])
+	}
+}
@@ -0,0 +1,166 @@
+package blackjack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/strategies"
+)
+
+// Index is the true count a play's action is taken at, at or above the count
+// when AtOrAbove, otherwise at or below it
+type Index struct {
+	Play      string `json:"play"`
+	TrueCount int    `json:"tc"`
+	AtOrAbove bool   `json:"atOrAbove"`
+}
+
+func (idx Index) Applies(tc int) bool {
+	if idx.AtOrAbove {
+		return tc >= idx.TrueCount
+	}
+	return tc <= idx.TrueCount
+}
+
+func (idx Index) ToString() string {
+	if idx.AtOrAbove {
+		return fmt.Sprintf("%s at %+d or above", idx.Play, idx.TrueCount)
+	}
+	return fmt.Sprintf("%s at %+d or below", idx.Play, idx.TrueCount)
+}
+
+func LoadIndexTable(path string) ([]Index, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	indices := []Index{}
+	if err := json.Unmarshal(data, &indices); err != nil {
+		return nil, fmt.Errorf("failed parsing index table %s: %w", path, err)
+	}
+	return indices, nil
+}
+
+func SaveIndexTable(path string, indices []Index) error {
+	data, err := json.MarshalIndent(indices, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+type deviationKey struct {
+	upcard int
+	total  int
+	soft   bool
+	pair   bool
+}
+
+type deviation struct {
+	play  Play
+	index Index
+}
+
+// Deviations overrides basic strategy with count based plays from an index table
+type Deviations struct {
+	plays     map[deviationKey]deviation
+	insurance *Index
+}
+
+func NewDeviations(indices []Index) (*Deviations, error) {
+	deviations := &Deviations{plays: map[deviationKey]deviation{}}
+	for _, idx := range indices {
+		idx := idx
+		play, err := FindPlay(idx.Play)
+		if err != nil {
+			return nil, err
+		}
+		if play.Insurance {
+			deviations.insurance = &idx
+			continue
+		}
+		deviations.plays[playKey(play)] = deviation{play: play, index: idx}
+	}
+	return deviations, nil
+}
+
+func playKey(play Play) deviationKey {
+	hand := core.Hand{}
+	for _, v := range play.PlayerCards {
+		hand.Cards = append(hand.Cards, core.Card{Value: v})
+	}
+	total, soft := hand.HandValue()
+	_, pair := hand.IsPair()
+	return deviationKey{upcard: play.DealerUpcard, total: total, soft: soft, pair: pair}
+}
+
+// Insure is true when insurance should be taken at the count
+func (dv *Deviations) Insure(tc int) bool {
+	return dv.insurance != nil && dv.insurance.Applies(tc)
+}
+
+// Apply swaps the basic strategy decision for the play's action or alternative
+// when the hand matches one of the indexed plays. Non pair plays apply to any
+// hand of the total that basic strategy doesn't split. A surrender is left
+// alone unless the index is for surrender
+func (dv *Deviations) Apply(rules *BlackjackGameRules, hand core.Hand, dealerUpcard core.Card,
+	basic PlayerDecision, tc int, splitCounter int) PlayerDecision {
+	if basic == PlayerDecisionNatural21 || hand.SplitAcesHand {
+		return basic
+	}
+	total, soft := hand.HandValue()
+	_, pair := hand.IsPair()
+	key := deviationKey{upcard: dealerUpcard.Value, total: total, soft: soft, pair: pair}
+	dev, exists := dv.plays[key]
+	if !exists {
+		if !pair || basic == PlayerDecisionSplit || basic == PlayerDecisionSplitAces {
+			return basic
+		}
+		// a pair that isn't split plays like any other hand of the total
+		key.pair = false
+		if dev, exists = dv.plays[key]; !exists {
+			return basic
+		}
+	}
+
+	if basic == PlayerDecisionSurrender && dev.play.Action != PlayerDecisionSurrender &&
+		dev.play.Alternative != PlayerDecisionSurrender {
+		// the hand's given up before the index's play comes into it
+		return basic
+	}
+	decision := dev.play.Alternative
+	if dev.index.Applies(tc) {
+		decision = dev.play.Action
+	}
+	switch decision {
+	case PlayerDecisionDouble:
+		if !rules.canDouble(hand) || (hand.SplitHand && !rules.DoubleAfterSplit) {
+			return basic
+		}
+	case PlayerDecisionSplit:
+		if !pair || splitCounter >= rules.MaxPlayerSplits {
+			return basic
+		}
+	}
+	return decision
+}
+
+// insuranceTrueCount is the count insurance is taken at, the playing count with
+// any ace side count applied
+func (rs *BlackjackGameRules) insuranceTrueCount(d core.Shoe) int {
+	if hl, ok := rs.TrackingStrategy.(*strategies.HighLowCountStrategy); ok {
+		return hl.Method.Convert(hl.InsuranceTrueCount(d))
+	}
+	return rs.playingTrueCount(d)
+}
+
+// playingTrueCount is the count playing decisions are made at, which leaves out
+// any betting only adjustments
+func (rs *BlackjackGameRules) playingTrueCount(d core.Shoe) int {
+	if hl, ok := rs.TrackingStrategy.(*strategies.HighLowCountStrategy); ok {
+		return hl.Method.Convert(hl.PlayingTrueCount(d))
+	}
+	return rs.TrackingStrategy.TrueCount(d)
+}
@@ -0,0 +1,104 @@
+package blackjack
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/strategies"
+)
+
+func TestDeviations(t *testing.T) {
+	rules := MakeTestRules()
+	deviations, err := NewDeviations([]Index{
+		{Play: "16v10", TrueCount: 0, AtOrAbove: true},
+		{Play: "TTv5", TrueCount: 5, AtOrAbove: true},
+		{Play: "10v10", TrueCount: 4, AtOrAbove: true},
+		{Play: "insurance", TrueCount: 3, AtOrAbove: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ten := core.Card{Value: 10}
+	if d := deviations.Apply(rules, MakeHand(10, 6), ten, PlayerDecisionHit, 0, 0); d != PlayerDecisionStand {
+		t.Fatalf("should stand 16 vs 10 at 0, got %s", d.ToString())
+	}
+	if d := deviations.Apply(rules, MakeHand(10, 6), ten, PlayerDecisionStand, -1, 0); d != PlayerDecisionHit {
+		t.Fatalf("should hit 16 vs 10 below 0, got %s", d.ToString())
+	}
+	if d := deviations.Apply(rules, MakeHand(10, 6), ten, PlayerDecisionSurrender, 0, 0); d != PlayerDecisionSurrender {
+		t.Fatalf("a stand index shouldn't undo a surrender, got %s", d.ToString())
+	}
+	if d := deviations.Apply(rules, MakeHand(5, 6, 5), ten, PlayerDecisionHit, 1, 0); d != PlayerDecisionStand {
+		t.Fatalf("should stand a 3 card 16 vs 10 at 1, got %s", d.ToString())
+	}
+	if d := deviations.Apply(rules, MakeHand(10, 10), core.Card{Value: 5}, PlayerDecisionStand, 5, 0); d != PlayerDecisionSplit {
+		t.Fatalf("should split tens vs 5 at 5, got %s", d.ToString())
+	}
+	if d := deviations.Apply(rules, MakeHand(10, 10), core.Card{Value: 5}, PlayerDecisionStand, 5, rules.MaxPlayerSplits); d != PlayerDecisionStand {
+		t.Fatalf("can't split past the max splits, got %s", d.ToString())
+	}
+	if d := deviations.Apply(rules, MakeHand(2, 4, 4), ten, PlayerDecisionHit, 5, 0); d != PlayerDecisionHit {
+		t.Fatalf("can't double a 3 card 10, got %s", d.ToString())
+	}
+	if d := deviations.Apply(NewSpanish21Game(), MakeHand(2, 4, 4), ten, PlayerDecisionHit, 5, 0); d != PlayerDecisionDouble {
+		t.Fatalf("should double a 3 card 10 when any cards can be doubled, got %s", d.ToString())
+	}
+	if !deviations.Insure(3) || deviations.Insure(2) {
+		t.Fatalf("should insure at 3 and up")
+	}
+	if _, err := NewDeviations([]Index{{Play: "17v10"}}); err == nil {
+		t.Fatalf("expected an error for an unknown play")
+	}
+}
+
+func TestFindIndex(t *testing.T) {
+	play, _ := FindPlay("16v10")
+	rising := map[int]TCResult{}
+	falling := map[int]TCResult{}
+	for tc := -5; tc <= 5; tc++ {
+		gain := 0.01 * (float64(tc) - 2.5)
+		rising[tc] = TCResult{Rounds: minIndexSamples, EV: gain * minIndexSamples}
+		falling[tc] = TCResult{Rounds: minIndexSamples, EV: -gain * minIndexSamples}
+	}
+	if idx, ok := FindIndex(play, rising); !ok || idx.TrueCount != 3 || !idx.AtOrAbove {
+		t.Fatalf("expected an index of 3 or above, got %s", idx.ToString())
+	}
+	if idx, ok := FindIndex(play, falling); !ok || idx.TrueCount != 2 || idx.AtOrAbove {
+		t.Fatalf("expected an index of 2 or below, got %s", idx.ToString())
+	}
+	if _, ok := FindIndex(play, map[int]TCResult{0: {Rounds: minIndexSamples}}); ok {
+		t.Fatalf("can't find an index from a single count")
+	}
+}
+
+func TestSimulateInsuranceIndex(t *testing.T) {
+	rules := MakeTestRules().SetPenetration(1.5)
+	rules.TrackingStrategy = strategies.InitHighLow(map[int]strategies.BidStrategy{})
+	insurance, _ := FindPlay("insurance")
+	gains, err := SimulatePlays(*rules, 6, 1000, []Play{insurance})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the published HiLo insurance index is +3
+	if idx, ok := FindIndex(insurance, gains[0]); !ok || idx.TrueCount < 2 || idx.TrueCount > 4 || !idx.AtOrAbove {
+		t.Fatalf("expected an insurance index around 3, got %s", idx.ToString())
+	}
+}
+
+func TestSimulatePlaysSeeded(t *testing.T) {
+	rules := MakeTestRules().SetPenetration(1.5).SetSeed(7)
+	rules.TrackingStrategy = strategies.InitHighLow(map[int]strategies.BidStrategy{})
+	play, _ := FindPlay("16v10")
+	first, err := SimulatePlays(*rules, 6, 10, []Play{play})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := SimulatePlays(*rules, 6, 10, []Play{play})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("the same seed should sim the same gains")
+	}
+}
@@ -0,0 +1,136 @@
+package blackjack
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+)
+
+// Distribution of simulated outcomes, kept sorted
+type Distribution struct {
+	values []float64
+}
+
+type HistogramBin struct {
+	Low   float64
+	High  float64
+	Count int
+}
+
+func NewDistribution(values []float64) Distribution {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	return Distribution{values: sorted}
+}
+
+func (d Distribution) Len() int {
+	return len(d.values)
+}
+
+func (d Distribution) Mean() float64 {
+	if len(d.values) == 0 {
+		return 0
+	}
+	sum := float64(0)
+	for _, v := range d.values {
+		sum += v
+	}
+	return sum / float64(len(d.values))
+}
+
+// StdDev is the population standard deviation
+func (d Distribution) StdDev() float64 {
+	if len(d.values) == 0 {
+		return 0
+	}
+	mean := d.Mean()
+	variance := float64(0)
+	for _, v := range d.values {
+		variance += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(variance / float64(len(d.values)))
+}
+
+// Percentile interpolates between the closest ranks, p is 0-100
+func (d Distribution) Percentile(p float64) float64 {
+	if len(d.values) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(d.values)-1)
+	low := int(math.Floor(rank))
+	high := int(math.Ceil(rank))
+	if low < 0 {
+		return d.values[0]
+	} else if high >= len(d.values) {
+		return d.values[len(d.values)-1]
+	}
+	return d.values[low] + (d.values[high]-d.values[low])*(rank-float64(low))
+}
+
+// ProbabilityBelow is the share of outcomes under x
+func (d Distribution) ProbabilityBelow(x float64) float64 {
+	if len(d.values) == 0 {
+		return 0
+	}
+	return float64(sort.SearchFloat64s(d.values, x)) / float64(len(d.values))
+}
+
+// Histogram splits the range of outcomes into evenly sized bins
+func (d Distribution) Histogram(bins int) []HistogramBin {
+	if len(d.values) == 0 || bins <= 0 {
+		return nil
+	}
+	low, high := d.values[0], d.values[len(d.values)-1]
+	width := (high - low) / float64(bins)
+	if width == 0 {
+		return []HistogramBin{{Low: low, High: high, Count: len(d.values)}}
+	}
+	histogram := make([]HistogramBin, bins)
+	for i := range histogram {
+		histogram[i].Low = low + width*float64(i)
+		histogram[i].High = low + width*float64(i+1)
+	}
+	for _, v := range d.values {
+		bin := int((v - low) / width)
+		if bin >= bins {
+			bin = bins - 1
+		}
+		histogram[bin].Count++
+	}
+	return histogram
+}
+
+// Bootstrap builds the distribution of results after `hours` hours of play by
+// summing hours drawn with replacement from the simulated ones, once per trial.
+// A part hour is a sampled hour's swing around the mean scaled by the root of
+// its share, so it keeps the mean and variance of that share of an hour
+func Bootstrap(hourly []float32, hours float32, trials int, seed uint64) Distribution {
+	if len(hourly) == 0 || trials <= 0 {
+		return Distribution{}
+	}
+	source := rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	if seed != 0 {
+		source = rand.New(rand.NewPCG(seed, seed))
+	}
+	whole := int(hours)
+	partial := float64(hours) - float64(whole)
+	mean := float64(0)
+	for _, h := range hourly {
+		mean += float64(h)
+	}
+	mean /= float64(len(hourly))
+	outcomes := make([]float64, trials)
+	for i := range outcomes {
+		total := float64(0)
+		for h := 0; h < whole; h++ {
+			total += float64(hourly[source.IntN(len(hourly))])
+		}
+		if partial > 0 {
+			swing := float64(hourly[source.IntN(len(hourly))]) - mean
+			total += mean*partial + swing*math.Sqrt(partial)
+		}
+		outcomes[i] = total
+	}
+	return NewDistribution(outcomes)
+}
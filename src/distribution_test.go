@@ -0,0 +1,112 @@
+package blackjack
+
+import (
+	"testing"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/strategies"
+)
+
+func TestDistribution(t *testing.T) {
+	dist := NewDistribution([]float64{5, 1, 4, 2, 3, -1, 0, 6, 7, 8, 9})
+	if dist.Percentile(50) != 4 {
+		t.Fatalf("expected a median of 4, got %f", dist.Percentile(50))
+	}
+	if dist.Percentile(0) != -1 || dist.Percentile(100) != 9 {
+		t.Fatalf("expected the min and max at 0 and 100, got %f %f", dist.Percentile(0), dist.Percentile(100))
+	}
+	if p := dist.Percentile(15); p != 0.5 {
+		t.Fatalf("expected the 15th percentile interpolated to 0.5, got %f", p)
+	}
+	if p := dist.ProbabilityBelow(0); p != 1.0/11 {
+		t.Fatalf("expected 1 in 11 below 0, got %f", p)
+	}
+	total := 0
+	for _, bin := range dist.Histogram(5) {
+		total += bin.Count
+	}
+	if total != dist.Len() {
+		t.Fatalf("histogram should hold every value, got %d", total)
+	}
+}
+
+func TestPlaySessions(t *testing.T) {
+	rules := MakeTestRules().SetPenetration(1.5)
+	rules.TrackingStrategy = strategies.InitFlatbetStrategy()
+	results, err := PlaySessions(*rules, 6, 50, SessionParams{Hours: 1}, 100, 10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Results) != 50 {
+		t.Fatalf("expected 50 sessions, got %d", len(results.Results))
+	}
+	if results.Rounds != 50*100 {
+		t.Fatalf("sessions without limits should play the full hour, got %d rounds", results.Rounds)
+	}
+
+	results, err = PlaySessions(*rules, 6, 50, SessionParams{Hours: 4, StopLoss: 5, WinGoal: 5}, 100, 10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results.Results {
+		// a round can swing at most 8 units past the limit with splits and doubles
+		if r < -13 || r > 13 {
+			t.Fatalf("session should have stopped near the limits, got %f", r)
+		}
+	}
+	if results.StopLosses+results.WinGoals != 50 {
+		t.Fatalf("every session should hit a limit in 4 hours, got %d", results.StopLosses+results.WinGoals)
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	constant := Bootstrap([]float32{2, 2, 2}, 10.5, 100, 1)
+	if constant.Percentile(1) != 21 || constant.Percentile(99) != 21 {
+		t.Fatalf("constant hours should always sum to 21, got %f-%f", constant.Percentile(1), constant.Percentile(99))
+	}
+
+	dist := Bootstrap([]float32{-10, 10}, 100, 5000, 1)
+	if dist.Len() != 5000 {
+		t.Fatalf("expected 5000 trials, got %d", dist.Len())
+	}
+	// a fair coin over 100 hours, mean 0 and sd 100
+	if mean := dist.Mean(); mean < -10 || mean > 10 {
+		t.Fatalf("expected a mean around 0, got %f", mean)
+	}
+	if sd := dist.StdDev(); sd < 90 || sd > 110 {
+		t.Fatalf("expected a standard deviation around 100, got %f", sd)
+	}
+	if p := dist.ProbabilityBelow(0); p < 0.4 || p > 0.5 {
+		t.Fatalf("expected to be behind a bit under half the time, got %f", p)
+	}
+
+	// a quarter of an hour has a quarter of the variance, half the sd
+	quarter := Bootstrap([]float32{-6, 14}, 0.25, 5000, 1)
+	if mean := quarter.Mean(); mean < 0.8 || mean > 1.2 {
+		t.Fatalf("expected a quarter of the hourly mean, got %f", mean)
+	}
+	if sd := quarter.StdDev(); sd < 4.9 || sd > 5.1 {
+		t.Fatalf("expected a standard deviation of 5, got %f", sd)
+	}
+}
+
+func TestSessionLimitOnLastRound(t *testing.T) {
+	rules := MakeTestRules().SetPenetration(0.5)
+	rules.TrackingStrategy = strategies.InitFlatbetStrategy()
+	shoe, err := PlayShoe(core.GenerateShoe(1), rules, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the same unshuffled shoe again, with the limit reached on its last round
+	stopped := false
+	_, err = playShoeUntil(core.GenerateShoe(1), rules, 100, func(rounds int, net float32) bool {
+		stopped = rounds >= shoe.Rounds()
+		return stopped
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stopped {
+		t.Fatalf("a limit reached on the last round of the shoe should stop the session")
+	}
+}
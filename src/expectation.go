@@ -0,0 +1,245 @@
+package blackjack
+
+import "github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+
+// The expectation calculator walks every possible deal from a shoe composition
+// and plays it with the ruleset's strategy. Cards drawn by the player are
+// removed as they're drawn, the dealer's outcomes are calculated from the shoe
+// after the initial deal. Splits are played as two independent hands. The
+// Spanish 21 rules are followed but bonuses aren't paid, they need the suits
+// and number of cards of each hand
+
+// Dealer final totals, 17 through 21 then bust
+type dealerOutcomes [6]float64
+
+const dealerBust = 5
+
+// addCard adds a card to a running hand total, tracking whether an ace is still counted as 11
+func addCard(total int, soft bool, value int) (int, bool) {
+	total += value
+	if value == 11 {
+		if soft {
+			total -= 10
+		} else {
+			soft = true
+		}
+	}
+	if total > 21 && soft {
+		total -= 10
+		soft = false
+	}
+	return total, soft
+}
+
+// blackjackCard is the hole card that gives the dealer a natural under the upcard, 0 for none
+func blackjackCard(upcard int) int {
+	switch upcard {
+	case 10:
+		return 11
+	case 11:
+		return 10
+	}
+	return 0
+}
+
+// dealerBlackjackChance is the chance the hole card makes a natural
+func dealerBlackjackChance(upcard int, comp core.Composition) float64 {
+	if bj := blackjackCard(upcard); bj != 0 {
+		return comp.Probability(bj)
+	}
+	return 0
+}
+
+// dealerOutcomes calculates the dealer's final totals given they've peeked and
+// don't have a natural
+func (rs *BlackjackGameRules) dealerOutcomes(upcard int, comp core.Composition) dealerOutcomes {
+	outcomes := dealerOutcomes{}
+	total, soft := addCard(0, false, upcard)
+	excluded := blackjackCard(upcard)
+	remaining := comp.Total() - comp[excluded]
+	for hole := 2; hole <= 11; hole++ {
+		if hole == excluded || comp[hole] == 0 {
+			continue
+		}
+		p := float64(comp[hole]) / float64(remaining)
+		t, s := addCard(total, soft, hole)
+		for i, o := range rs.dealerDraw(t, s, comp.Remove(hole)) {
+			outcomes[i] += p * o
+		}
+	}
+	return outcomes
+}
+
+func (rs *BlackjackGameRules) dealerDraw(total int, soft bool, comp core.Composition) dealerOutcomes {
+	outcomes := dealerOutcomes{}
+	if total > 21 {
+		outcomes[dealerBust] = 1
+		return outcomes
+	}
+	if rs.dealerDecision(total, soft) == PlayerDecisionStand {
+		outcomes[total-17] = 1
+		return outcomes
+	}
+	remaining := float64(comp.Total())
+	for value := 2; value <= 11; value++ {
+		if comp[value] == 0 {
+			continue
+		}
+		p := float64(comp[value]) / remaining
+		t, s := addCard(total, soft, value)
+		for i, o := range rs.dealerDraw(t, s, comp.Remove(value)) {
+			outcomes[i] += p * o
+		}
+	}
+	return outcomes
+}
+
+type dealerKey struct {
+	upcard int
+	comp   core.Composition
+}
+
+// handExpectation plays out player hands against a fixed set of dealer outcomes
+type handExpectation struct {
+	rules  *BlackjackGameRules
+	upcard core.Card
+	dealer dealerOutcomes
+}
+
+func (he *handExpectation) stand(total int) float64 {
+	if total > 21 {
+		return -1
+	}
+	ev := he.dealer[dealerBust]
+	for dealerTotal := 17; dealerTotal <= 21; dealerTotal++ {
+		if dealerTotal < total || (total == 21 && he.rules.Player21Wins) {
+			ev += he.dealer[dealerTotal-17]
+		} else if dealerTotal > total {
+			ev -= he.dealer[dealerTotal-17]
+		}
+	}
+	return ev
+}
+
+func (he *handExpectation) hand(hand core.Hand, comp core.Composition, splitCounter int) float64 {
+	decision := he.rules.MakePlayerDecision(hand, he.upcard, splitCounter)
+	return he.decision(hand, comp, splitCounter, decision)
+}
+
+// decision is the expectation of making the decision then playing on with the ruleset
+func (he *handExpectation) decision(hand core.Hand, comp core.Composition, splitCounter int, decision PlayerDecision) float64 {
+	remaining := float64(comp.Total())
+	ev := float64(0)
+	switch decision {
+	case PlayerDecisionHit:
+		for value := 2; value <= 11; value++ {
+			if comp[value] == 0 {
+				continue
+			}
+			p := float64(comp[value]) / remaining
+			drawn := hand
+			drawn.Cards = append(append([]core.Card{}, hand.Cards...), core.Card{Value: value})
+			if total, _ := drawn.HandValue(); total > 21 {
+				ev -= p
+			} else {
+				ev += p * he.hand(drawn, comp.Remove(value), splitCounter)
+			}
+		}
+		return ev
+	case PlayerDecisionDouble:
+		total, soft := hand.HandValue()
+		for value := 2; value <= 11; value++ {
+			if comp[value] == 0 {
+				continue
+			}
+			t, _ := addCard(total, soft, value)
+			doubled := 2 * he.stand(t)
+			if he.rules.DoubleRescue && t <= 21 && doubled < -1 {
+				doubled = -1
+			}
+			ev += float64(comp[value]) / remaining * doubled
+		}
+		return ev
+	case PlayerDecisionSurrender:
+		return -0.5
+	case PlayerDecisionSplit, PlayerDecisionSplitAces:
+		if decision == PlayerDecisionSplit {
+			splitCounter++
+		}
+		for value := 2; value <= 11; value++ {
+			if comp[value] == 0 {
+				continue
+			}
+			split := core.Hand{
+				Cards:         []core.Card{hand.Cards[0], {Value: value}},
+				SplitHand:     decision == PlayerDecisionSplit,
+				SplitAcesHand: decision == PlayerDecisionSplitAces,
+			}
+			ev += float64(comp[value]) / remaining * he.hand(split, comp.Remove(value), splitCounter)
+		}
+		return 2 * ev
+	}
+	total, _ := hand.HandValue()
+	return he.stand(total)
+}
+
+// initialDeal builds the player's hand and the shoe left after the initial deal
+func initialDeal(playerCards []int, upcard int, comp core.Composition) (core.Hand, core.Composition) {
+	hand := core.Hand{}
+	for _, v := range playerCards {
+		hand.Cards = append(hand.Cards, core.Card{Value: v})
+		comp = comp.Remove(v)
+	}
+	return hand, comp.Remove(upcard)
+}
+
+// ExpectedValue is the player's expectation per unit bet, playing the ruleset's
+// strategy off the top of a shoe with the given composition
+func (rs *BlackjackGameRules) ExpectedValue(comp core.Composition) float64 {
+	total := float64(comp.Total())
+	dealerCache := map[dealerKey]dealerOutcomes{}
+	ev := float64(0)
+	for upcard := 2; upcard <= 11; upcard++ {
+		for p1 := 2; p1 <= 11; p1++ {
+			for p2 := p1; p2 <= 11; p2++ {
+				hand, remaining := initialDeal([]int{p1, p2}, upcard, comp)
+				if remaining[p1] < 0 || remaining[p2] < 0 || remaining[upcard] < 0 {
+					continue
+				}
+				// p1 then p2 or p2 then p1, then the upcard
+				p := float64(comp[p1]) / total
+				if p1 == p2 {
+					p *= float64(comp[p2]-1) / (total - 1)
+				} else {
+					p *= 2 * float64(comp[p2]) / (total - 1)
+				}
+				p *= float64(comp[upcard]-btoi(upcard == p1)-btoi(upcard == p2)) / (total - 2)
+
+				pBJ := dealerBlackjackChance(upcard, remaining)
+				if value, _ := hand.HandValue(); value == 21 {
+					if rs.Player21Wins {
+						pBJ = 0
+					}
+					ev += p * (1 - pBJ) * float64(blackjackPayout)
+					continue
+				}
+				key := dealerKey{upcard: upcard, comp: remaining}
+				dealer, exists := dealerCache[key]
+				if !exists {
+					dealer = rs.dealerOutcomes(upcard, remaining)
+					dealerCache[key] = dealer
+				}
+				he := handExpectation{rules: rs, upcard: core.Card{Value: upcard}, dealer: dealer}
+				ev += p * (-pBJ + (1-pBJ)*he.hand(hand, remaining, 0))
+			}
+		}
+	}
+	return ev
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -1,6 +1,7 @@
 package blackjack
 
 import (
+	"errors"
 	"math"
 
 	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
@@ -15,10 +16,51 @@ type BlackjackGameRules struct {
 	ReSplitAces      bool
 	MaxPlayerSplits  int
 	DoubleAfterSplit bool
-	Penetration      float32
-	TrackingStrategy strategies.TrackingStrategy
+	Penetration      float32 // decks cut off
 
-	UseSimpleDeviations bool // use insurance after TC 3+ & no hit 12
+	// Cut card placement, PenetrationDealt is the share of the shoe dealt and overrides Penetration
+	PenetrationDealt   float32
+	CutCardStdDev      float32 // in cards
+	CutCardExtraRounds int     // rounds dealt after the one the cut card comes out in
+	TrackingStrategy   strategies.TrackingStrategy
+
+	// Table limits, all in currency. With no unit size bids are placed as is
+	UnitSize float32
+	TableMin float32
+	TableMax float32 // 0 for no max
+	ChipSize float32 // bets are rounded to the nearest chip, 0 for no rounding
+
+	// Back counting, watch the shoe and only play from WongInTC until the count drops below WongOutTC
+	Wonging   bool
+	WongInTC  int
+	WongOutTC int
+
+	UseSimpleDeviations bool              // use insurance after TC 3+ & no hit 12
+	Deviations          *Deviations       // count based plays, nil to play basic strategy only
+	Errors              *ErrorModel       // player mistakes, nil for perfect play
+	Camouflage          *Camouflage       // cover betting rules, nil to bet the spread as is
+	HoleCard            *HoleCardExposure // a dealer flashing the hole card, nil for a careful dealer
+	Seed                uint64            // seeds the shuffles so runs can be compared, 0 for random
+
+	// Continuous shuffling machine, discards go back in after every round behind CSMReservoir held out cards
+	ContinuousShuffle bool
+	CSMReservoir      int
+	ShuffleRounds     float32              // table time a hand shuffle takes, in rounds
+	InfiniteDeck      bool                 // draw every card independently, shoes end at the cut card's depth
+	Deck              core.DeckComposition // the cards in each deck, standard when zero
+
+	Shuffler core.Shuffler // the dealer's shuffle, nil for a perfect one
+
+	// Cards burned after each shuffle, BurnVisible shows them to the table
+	BurnCards   int
+	BurnVisible bool
+
+	// Spanish 21 style rules, all off for a standard game
+	Player21Wins   bool     // a player's 21 beats the dealer's, blackjack included
+	DoubleAnyCards bool     // double on any number of cards, not just the first two
+	LateSurrender  bool     // give up half the bet on the first two cards once the dealer has peeked
+	DoubleRescue   bool     // give up a doubled hand, losing the original bet
+	Bonuses        *Bonuses // bonus payouts on a 21, nil for none
 }
 
 func NewBlackjackGameRules(rules *Ruleset) *BlackjackGameRules {
@@ -30,6 +72,7 @@ func NewBlackjackGameRules(rules *Ruleset) *BlackjackGameRules {
 		UseSimpleDeviations: false,
 		ReSplitAces:         false,
 		TrackingStrategy:    strategies.InitFlatbetStrategy(),
+		Deck:                core.StandardDeck,
 	}
 }
 
@@ -38,6 +81,17 @@ func (bj *BlackjackGameRules) SetPenetration(pen float32) *BlackjackGameRules {
 	return bj
 }
 
+func (bj *BlackjackGameRules) SetPenetrationDealt(v float32) *BlackjackGameRules {
+	bj.PenetrationDealt = v
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetCutCardPlacement(stdDev float32, extraRounds int) *BlackjackGameRules {
+	bj.CutCardStdDev = stdDev
+	bj.CutCardExtraRounds = extraRounds
+	return bj
+}
+
 func (bj *BlackjackGameRules) SetDealerHitsSoft17(v bool) *BlackjackGameRules {
 	bj.DealerHitsSoft17 = v
 	return bj
@@ -58,37 +112,220 @@ func (bj *BlackjackGameRules) SetDoubleAfterSplit(v bool) *BlackjackGameRules {
 	return bj
 }
 
+func (bj *BlackjackGameRules) SetUnitSize(v float32) *BlackjackGameRules {
+	bj.UnitSize = v
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetTableLimits(min float32, max float32) *BlackjackGameRules {
+	bj.TableMin = min
+	bj.TableMax = max
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetChipSize(v float32) *BlackjackGameRules {
+	bj.ChipSize = v
+	return bj
+}
+
+// PlaceBet turns a bid in units into the bet that can actually be made at the
+// table, rounded to chips and held to the table limits. Returned in units
+func (bj *BlackjackGameRules) PlaceBet(units float32) float32 {
+	if bj.UnitSize <= 0 {
+		return units
+	}
+	bet := units * bj.UnitSize
+	if bj.ChipSize > 0 {
+		bet = float32(math.Round(float64(bet/bj.ChipSize))) * bj.ChipSize
+	}
+	if bj.TableMax > 0 && bet > bj.TableMax {
+		bet = bj.TableMax
+	}
+	if bet < bj.TableMin {
+		bet = bj.TableMin
+	}
+	return bet / bj.UnitSize
+}
+
+func (bj *BlackjackGameRules) SetWonging(inTC int, outTC int) *BlackjackGameRules {
+	bj.Wonging = true
+	bj.WongInTC = inTC
+	bj.WongOutTC = outTC
+	return bj
+}
+
 func (bj *BlackjackGameRules) SetUseSimpleDeviations(v bool) *BlackjackGameRules {
 	bj.UseSimpleDeviations = v
 	return bj
 }
 
-func PlayGame(rules BlackjackGameRules, decks int, shoes int, bankrole float32, handsPerHour float32) GameResults {
-	deck := core.GenerateShoe(decks).Shuffle()
+func (bj *BlackjackGameRules) SetDeviations(v *Deviations) *BlackjackGameRules {
+	bj.Deviations = v
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetErrorModel(v *ErrorModel) *BlackjackGameRules {
+	bj.Errors = v
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetCamouflage(v *Camouflage) *BlackjackGameRules {
+	bj.Camouflage = v
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetContinuousShuffle(reservoir int) *BlackjackGameRules {
+	bj.ContinuousShuffle = true
+	bj.CSMReservoir = reservoir
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetInfiniteDeck(v bool) *BlackjackGameRules {
+	bj.InfiniteDeck = v
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetDeck(v core.DeckComposition) *BlackjackGameRules {
+	bj.Deck = v
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetPlayer21Wins(v bool) *BlackjackGameRules {
+	bj.Player21Wins = v
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetDoubleAnyCards(v bool) *BlackjackGameRules {
+	bj.DoubleAnyCards = v
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetLateSurrender(v bool) *BlackjackGameRules {
+	bj.LateSurrender = v
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetDoubleRescue(v bool) *BlackjackGameRules {
+	bj.DoubleRescue = v
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetBonuses(v *Bonuses) *BlackjackGameRules {
+	bj.Bonuses = v
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetShuffleRounds(v float32) *BlackjackGameRules {
+	bj.ShuffleRounds = v
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetBurn(cards int, visible bool) *BlackjackGameRules {
+	bj.BurnCards = cards
+	bj.BurnVisible = visible
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetHoleCardExposure(v *HoleCardExposure) *BlackjackGameRules {
+	bj.HoleCard = v
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetShuffler(v core.Shuffler) *BlackjackGameRules {
+	bj.Shuffler = v
+	return bj
+}
+
+func (bj *BlackjackGameRules) SetSeed(v uint64) *BlackjackGameRules {
+	bj.Seed = v
+	return bj
+}
+
+func PlayGame(rules BlackjackGameRules, decks int, shoes int, bankrole float32, handsPerHour float32) (GameResults, error) {
+	deck := rules.dealer(decks, rules.Seed)
 	// create a new instance of the tracking strategy as to not share state
 	// with the other threads
-	rules.TrackingStrategy = rules.TrackingStrategy.Instance()
+	rules.TrackingStrategy = rules.instanceStrategy()
+	rules.Errors = rules.Errors.Instance(rules.Seed)
+	rules.Camouflage = rules.Camouflage.Instance(rules.Seed)
+	rules.HoleCard = rules.HoleCard.Instance(rules.Seed)
+	if rules.Errors != nil {
+		rules.Errors.Attach(rules.TrackingStrategy)
+	}
 
-	deck.PreviewCard = func(c core.Card) {
+	deck.SetPreviewCard(func(c core.Card) {
 		rules.TrackingStrategy.Update(c)
-	}
+		if rules.Errors != nil {
+			rules.Errors.Count(rules.TrackingStrategy)
+		}
+	})
 	totalGames := 0
 
 	handAVs := make([]float32, 0, shoes*50) // shoes average ~45 hands heads up
+	roundAVs := []float32{}
 	aggregatedResults := GameResults{}
+	startingBankrole := bankrole
 	for i := 0; i < shoes; i++ {
-		result := PlayShoe(deck, &rules, bankrole)
+		result, err := PlayShoe(deck, &rules, bankrole)
+		if err != nil {
+			return aggregatedResults, err
+		}
+		if result.Result <= 0 {
+			result.Ruins++
+		}
+		if sizer, ok := rules.TrackingStrategy.(strategies.BankrollAware); ok && sizer.SizesByBankroll() {
+			// the bankroll carries between shoes for strategies that resize off it,
+			// after a bust they start over with a fresh bankroll
+			bankrole = result.Result
+			if bankrole <= 0 {
+				bankrole = startingBankrole
+			}
+		}
 		if hl, ok := rules.TrackingStrategy.(*strategies.HighLowCountStrategy); ok {
 			result.BidsByTC = hl.BidsByTC
 			result.AvgTC = hl.AggregatedTC / float32(hl.Updates)
 			result.HighTC = hl.HighTC
 			result.LowTC = hl.LowTC
+			if hl.AceSideCount != nil {
+				result.AceAdjustedBids = hl.AceSideCount.AdjustedBids
+				hl.AceSideCount.AdjustedBids = 0
+			}
+		} else if uc, ok := rules.TrackingStrategy.(*strategies.UnbalancedCountStrategy); ok {
+			result.BidsByTC = uc.BidsByRC
+		} else if st, ok := rules.TrackingStrategy.(*strategies.ShuffleTrackingStrategy); ok {
+			result.BidsByTC = st.BidsByTC
+		} else if as, ok := rules.TrackingStrategy.(*strategies.AceSequencingStrategy); ok {
+			result.KeyCards, result.SequencedBids, result.SequencedAces = as.KeyCards, as.SequencedBids, as.SequencedAces
+			as.KeyCards, as.SequencedBids, as.SequencedAces = 0, 0, 0
+		}
+		if rules.Camouflage != nil {
+			result.CamouflagedBids = rules.Camouflage.Adjusted
+			rules.Camouflage.Adjusted = 0
+		}
+		if rules.Errors != nil {
+			result.ActionErrors = rules.Errors.ActionErrors
+			result.Miscounts = rules.Errors.Miscounts
+			rules.Errors.ActionErrors = 0
+			rules.Errors.Miscounts = 0
+		}
+		if rules.HoleCard != nil {
+			result.ExposedHoleCards = rules.HoleCard.Exposed
+			rules.HoleCard.Exposed = 0
+		}
+		if !rules.ContinuousShuffle && !rules.InfiniteDeck {
+			result.Shuffles++
 		}
 		rules.TrackingStrategy.Shuffle()
-		deck.Shuffle()
+		deck.Reshuffle()
 		totalGames++
 		aggregatedResults = AggregateResults(aggregatedResults, result)
 		handAVs = append(handAVs, result.HandAVs...)
+		roundAVs = append(roundAVs, result.RoundAVs...)
+	}
+	// observed rounds take up table time too, so hours are made of every round when wonging
+	hourlyAVs := handAVs
+	if rules.Wonging {
+		hourlyAVs = roundAVs
 	}
 	// calculate the population standard dev
 	evAgg := float32(0)
@@ -98,12 +335,14 @@ func PlayGame(rules BlackjackGameRules, decks int, shoes int, bankrole float32,
 	hourlyOverallTotal := float32(0)
 	for _, av := range handAVs {
 		evAgg += av
+	}
+	for _, av := range hourlyAVs {
 		hourlyAgg += av
 		hourlyHandCounter++
 		// this is truly horrendous and should be cleaned up, but in order to calculate hourly standard
 		// dev you need to calculate standard dev across the aggregated hourly AVs instead of
 		// the individual hand AVs
-		if hourlyHandCounter > handsPerHour {
+		if hourlyHandCounter >= handsPerHour {
 			hourlyOverallTotal += hourlyAgg
 			handsGroupedHourly = append(handsGroupedHourly, hourlyAgg)
 			hourlyHandCounter = 0
@@ -123,55 +362,112 @@ func PlayGame(rules BlackjackGameRules, decks int, shoes int, bankrole float32,
 
 	aggregatedResults.HourlyEVVariance = float32(math.Sqrt(float64(hourlyVariance) / float64(len(handsGroupedHourly))))
 	aggregatedResults.EVVariance = float32(math.Sqrt(float64(varianceAgg) / float64(len(handAVs))))
+	aggregatedResults.Result = bankrole
+	aggregatedResults.HourlyAVs = handsGroupedHourly
 	aggregatedResults.HandAVs = nil // save some mem
-	return aggregatedResults
+	aggregatedResults.RoundAVs = nil
+	return aggregatedResults, nil
+}
+
+func PlayHand(d core.Shoe, rules *BlackjackGameRules) []core.HandResult {
+	bidStrategy := rules.TrackingStrategy.Bid(d)
+	units := bidStrategy.Units
+	if rules.Camouflage != nil {
+		units = rules.Camouflage.Adjust(units)
+	}
+	bet := rules.PlaceBet(units)
+	if bidStrategy.Hands <= 1 {
+		return PlayRound(d, rules, bet)
+	}
+	// spread to more spots, each with the same bet
+	bets := make([]float32, bidStrategy.Hands)
+	for i := range bets {
+		bets[i] = bet
+	}
+	results := []core.HandResult{}
+	for _, seat := range PlaySeats(d, rules, bets) {
+		results = append(results, seat...)
+	}
+	return results
+}
+
+// PlayRound deals and plays out a single round with an already placed bet
+func PlayRound(d core.Shoe, rules *BlackjackGameRules, perHandBid float32) []core.HandResult {
+	return PlaySeats(d, rules, []float32{perHandBid})[0]
 }
 
-func PlayHand(d *core.Deck, rules *BlackjackGameRules) []core.HandResult {
-	bidStrategy := rules.TrackingStrategy.Bid(*d)
-	perHandBid := bidStrategy.Units
-	playerCards := core.Hand{}
+// PlaySeats deals and plays out a round with a player at each seat, in order,
+// against the one dealer hand. Results are per seat, with no seats the round is
+// only watched
+func PlaySeats(d core.Shoe, rules *BlackjackGameRules, bets []float32) [][]core.HandResult {
+	seats := make([]core.Hand, len(bets))
 	dealerCards := core.Hand{}
-	// TODO (bs): support multiple hands
-	playerCards.Cards = append(playerCards.Cards, d.Deal())
-	dealerCards.Cards = append(dealerCards.Cards, d.Deal())
-	playerCards.Cards = append(playerCards.Cards, d.Deal())
+	for i := range seats {
+		seats[i].Cards = append(seats[i].Cards, d.Deal())
+	}
+	// the hole card goes face down, the second card is the upcard
+	dealerCards.Cards = append(dealerCards.Cards, d.DealFaceDown())
+	for i := range seats {
+		seats[i].Cards = append(seats[i].Cards, d.Deal())
+	}
 	dealerCards.Cards = append(dealerCards.Cards, d.Deal())
 	dealerUpcard := dealerCards.Cards[1]
 
-	//insurance := false
-	if dealerUpcard.Value == 11 { // insurance?
-		// if rules.UseHighLowCounting {
-		// 	tc := d.TrueCount()
-		// 	if rules.UseSimpleDeviations && tc >= 3 {
-		// 		insurance = true
-		// 	}
-		// }
+	insure := dealerUpcard.Value == 11 && rules.Deviations != nil && rules.Deviations.Insure(rules.insuranceTrueCount(d))
+	dealerNatural := dealerCards.IsNatural() && dealerCards.Cards[0].Value == 10 && dealerUpcard.Value == 11
+	flashed := rules.HoleCard != nil && rules.HoleCard.Flash(dealerCards.Cards[0])
+	if flashed {
+		// the player counts the hole card as soon as it's seen
+		d.TurnOver(dealerCards.Cards[0])
+		if dealerUpcard.Value == 11 {
+			// no guessing at insurance with the hole card in view
+			insure = dealerNatural
+		}
 	}
 
-	playerHands := []core.Hand{playerCards}
-	// Play the hand if the dealer does not have 21
-	if dealerValue, _ := dealerCards.HandValue(); dealerValue != 21 {
+	playerHands := make([][]core.Hand, len(seats))
+	// with nobody seated the dealer plays out against the rest of the table
+	allBusted := len(seats) > 0
+	dealerValue, _ := dealerCards.HandValue()
+	for i, playerCards := range seats {
+		playerHands[i] = []core.Hand{playerCards}
+		// Play the hand if the dealer does not have 21
+		if dealerValue == 21 {
+			continue
+		}
 		splitCounter := 0
-		playerHands = rules.PlayPlayerHand(playerCards, dealerUpcard, d, bidStrategy.Units, &splitCounter)
-		allBusted := true
-		for _, v := range playerHands {
-			if handVal, _ := v.HandValue(); handVal <= 21 {
+		playerHands[i] = rules.PlayPlayerHand(playerCards, dealerUpcard, d, bets[i], &splitCounter)
+		for _, v := range playerHands[i] {
+			if handVal, _ := v.HandValue(); handVal <= 21 && !v.Surrendered {
 				allBusted = false
-				break
 			}
 		}
-		if !allBusted {
-			dealerCards = rules.PlayDealerHand(dealerCards, d)
-		}
+	}
+	if !flashed {
+		d.TurnOver(dealerCards.Cards[0])
+	}
+	if dealerValue != 21 && !allBusted {
+		dealerCards = rules.PlayDealerHand(dealerCards, d)
 	}
 
-	results := make([]core.HandResult, 0, len(playerHands))
-	totalChange := float32(0)
-	for _, h := range playerHands {
-		handResult := CalculateHandResult(h, dealerCards, perHandBid)
-		totalChange += handResult.AV
-		results = append(results, handResult)
+	if rules.HoleCard != nil {
+		rules.HoleCard.Hide()
+	}
+
+	results := make([][]core.HandResult, len(seats))
+	for i, hands := range playerHands {
+		results[i] = make([]core.HandResult, 0, len(hands))
+		for _, h := range hands {
+			results[i] = append(results[i], rules.HandResult(h, dealerCards, bets[i]))
+		}
+		if insure {
+			// half the bet, paying 2:1 on a dealer blackjack. Settles with the first hand
+			if dealerNatural {
+				results[i][0].AV += bets[i]
+			} else {
+				results[i][0].AV -= bets[i] / 2
+			}
+		}
 	}
 	return results
 }
@@ -210,16 +506,29 @@ func CalculateHandResult(playerHand core.Hand, dealerHand core.Hand, bid float32
 }
 
 func (rs *BlackjackGameRules) PlayPlayerHand(playerHand core.Hand, dealerUpcard core.Card,
-	deck *core.Deck, bid float32, splitCounter *int) []core.Hand {
+	deck core.Shoe, bid float32, splitCounter *int) []core.Hand {
 	finished := false
 	for {
 		decision := rs.MakePlayerDecision(playerHand, dealerUpcard, *splitCounter)
+		if rs.Deviations != nil {
+			decision = rs.Deviations.Apply(rs, playerHand, dealerUpcard, decision, rs.playingTrueCount(deck), *splitCounter)
+		}
+		if rs.HoleCard != nil {
+			decision = rs.HoleCard.Decide(rs, playerHand, dealerUpcard, deck, decision, *splitCounter)
+		}
+		if rs.Errors != nil {
+			decision = rs.Errors.Decide(rs, playerHand, decision, *splitCounter)
+		}
 		switch decision {
 		case PlayerDecisionNatural21:
 			finished = true
 		case PlayerDecisionDouble:
 			playerHand.Cards = append(playerHand.Cards, deck.Deal())
 			playerHand.Doubled = true
+			playerHand.Surrendered = rs.rescue(playerHand, dealerUpcard)
+			finished = true
+		case PlayerDecisionSurrender:
+			playerHand.Surrendered = true
 			finished = true
 		case PlayerDecisionSplitAces:
 			hands := make([]core.Hand, 0, 4)
@@ -250,7 +559,7 @@ func (rs *BlackjackGameRules) PlayPlayerHand(playerHand core.Hand, dealerUpcard
 	return []core.Hand{playerHand}
 }
 
-func (rs *BlackjackGameRules) PlayDealerHand(dealerHand core.Hand, deck *core.Deck) core.Hand {
+func (rs *BlackjackGameRules) PlayDealerHand(dealerHand core.Hand, deck core.Shoe) core.Hand {
 	for {
 		decision := rs.MakeDealerDecision(dealerHand)
 		if decision == PlayerDecisionHit {
@@ -262,16 +571,77 @@ func (rs *BlackjackGameRules) PlayDealerHand(dealerHand core.Hand, deck *core.De
 	return dealerHand
 }
 
-func PlayShoe(deck *core.Deck, rules *BlackjackGameRules, bankrole float32) GameResults {
+// PlayShoe plays the shoe down to the cut card. It fails with core.ErrOutOfCards
+// when the deck runs out before then
+func PlayShoe(deck core.Shoe, rules *BlackjackGameRules, bankrole float32) (GameResults, error) {
+	return playShoeUntil(deck, rules, bankrole, nil)
+}
+
+// recoverOutOfCards turns a deck running out mid-round into err. Cards are dealt
+// with Deal, which panics with core.ErrOutOfCards when there are none left
+func recoverOutOfCards(err *error) {
+	if r := recover(); r != nil {
+		if e, ok := r.(error); ok && errors.Is(e, core.ErrOutOfCards) {
+			*err = e
+			return
+		}
+		panic(r)
+	}
+}
+
+// playShoeUntil plays the shoe out, or until stop returns true after a round. stop
+// is given the rounds sat through and the net result so far
+func playShoeUntil(deck core.Shoe, rules *BlackjackGameRules, bankrole float32, stop func(rounds int, net float32) bool) (results GameResults, err error) {
+	defer recoverOutOfCards(&err)
 	before := bankrole
 	netWins := 0
 	netLosses := 0
 	blackjacks := 0
+	surrenders := 0
+	rescues := 0
+	bonuses := 0
 	totalHands := 0
+	observedHands := 0
 	handAVs := make([]float32, 0, 50)
+	var roundAVs []float32
+	tcResults := map[int]TCResult{}
+	sizer, resizing := rules.TrackingStrategy.(strategies.BankrollAware)
+	seated := !rules.Wonging
+	if shoe, ok := deck.(*core.Deck); ok && !shoe.HasCutCard() {
+		// a shoe built outside the rules is cut at their penetration
+		shoe.SetCutCard(rules.cutCard())
+	}
+	deck.Burn()
 	for {
+		tc := rules.TrackingStrategy.TrueCount(deck)
+		if rules.Wonging {
+			if !seated && tc >= rules.WongInTC {
+				seated = true
+			} else if seated && tc < rules.WongOutTC {
+				seated = false
+			}
+			if !seated {
+				// sit the round out, the cards still get counted as they're dealt
+				observedHands++
+				PlaySeats(deck, rules, nil)
+				rules.endRound(deck)
+				roundAVs = append(roundAVs, 0)
+				if stop != nil && stop(totalHands+observedHands, bankrole-before) {
+					break
+				}
+				if rules.shoeFinished(deck) {
+					break
+				}
+				continue
+			}
+		}
+
 		totalHands++
+		if resizing {
+			sizer.SetBankroll(bankrole)
+		}
 		handResults := PlayHand(deck, rules)
+		rules.endRound(deck)
 		handAV := float32(0)
 		for _, r := range handResults {
 			bankrole += r.AV
@@ -281,26 +651,52 @@ func PlayShoe(deck *core.Deck, rules *BlackjackGameRules, bankrole float32) Game
 			} else if bankrole < before {
 				netLosses++
 			}
-			if r.Result == core.HandResultBlackjack {
+			switch r.Result {
+			case core.HandResultBlackjack:
 				blackjacks++
+			case core.HandResultSurrender:
+				surrenders++
+			case core.HandResultRescue:
+				rescues++
+			case core.HandResultBonus:
+				bonuses++
 			}
 		}
 		handAVs = append(handAVs, handAV)
+		if rules.Camouflage != nil {
+			rules.Camouflage.Settle(handAV)
+		}
+		if rules.Wonging {
+			roundAVs = append(roundAVs, handAV)
+		}
+		tcResult := tcResults[tc]
+		tcResult.Add(handAV)
+		tcResults[tc] = tcResult
 		if bankrole <= 0 {
 			break
 		}
-		if deck.Remaining() < int(core.DeckSize*rules.Penetration) {
+		// stop sees every round, the last one of the shoe too
+		if stop != nil && stop(totalHands+observedHands, bankrole-before) {
+			break
+		}
+		if rules.shoeFinished(deck) {
 			break
 		}
 	}
 	return GameResults{
 		Result:     bankrole,
 		Hands:      totalHands,
+		Observed:   observedHands,
 		Blackjacks: blackjacks,
+		Surrenders: surrenders,
+		Rescues:    rescues,
+		Bonuses:    bonuses,
 		Wins:       netWins,
 		Losses:     netLosses,
 		Pushes:     totalHands - netWins - netLosses,
 		EV:         bankrole - before,
+		TCResults:  tcResults,
 		HandAVs:    handAVs,
-	}
+		RoundAVs:   roundAVs,
+	}, nil
 }
@@ -1,10 +1,12 @@
 package blackjack
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/strategies"
 )
 
 func Check(t *testing.T, check bool, message string) {
@@ -34,6 +36,25 @@ func makeTestDeck() *core.Deck {
 	}
 }
 
+// playTestGame plays the shoes from a six deck shoe at 100 rounds an hour
+func playTestGame(t *testing.T, rules BlackjackGameRules, shoes int, bankrole float32) GameResults {
+	t.Helper()
+	results, err := PlayGame(rules, 6, shoes, bankrole, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return results
+}
+
+func scriptedDeck(t *testing.T, script string) *core.Deck {
+	t.Helper()
+	deck, err := core.NewScriptedDeck(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return deck
+}
+
 func PlayDealerHand(t *testing.T, dealerHand core.Hand, rules *BlackjackGameRules) core.Hand {
 	if rules == nil {
 		rules = MakeTestRules()
@@ -268,3 +289,133 @@ func Test_Payouts(t *testing.T) {
 		ExpectHandResult(t, result, test.ExpectedResult, test.PlayerHand.ToString())
 	}
 }
+
+func Test_PlaceBet(t *testing.T) {
+	rules := MakeTestRules()
+	Check(t, rules.PlaceBet(2.3) == 2.3, "bets should be placed as is without a unit size")
+
+	rules.SetUnitSize(10).SetTableLimits(15, 100).SetChipSize(5)
+	tests := []struct {
+		Units    float32
+		Expected float32
+	}{
+		{Units: 1, Expected: 1.5},   // raised to the table min
+		{Units: 2.3, Expected: 2.5}, // $23 rounds to $25
+		{Units: 2.2, Expected: 2},   // $22 rounds to $20
+		{Units: 12, Expected: 10},   // capped at the table max
+	}
+	for _, test := range tests {
+		bet := rules.PlaceBet(test.Units)
+		Check(t, bet == test.Expected, fmt.Sprintf("%f units should bet %f, got %f", test.Units, test.Expected, bet))
+	}
+}
+
+func Test_PlayHandSpreadsToSpots(t *testing.T) {
+	// 2 spots of 3 units off an unshuffled deck. 2/5 and 3/6 vs 7 with 4 in the
+	// hole, the first spot busts drawing 8 and 9, the second stands on 19 from
+	// the 10 and the dealer makes 21 with the J
+	rules := MakeTestRules()
+	rules.TrackingStrategy = strategies.InitHighLow(map[int]strategies.BidStrategy{0: {Hands: 2, Units: 3}})
+	results := PlayHand(core.GenerateShoe(1), rules)
+	Check(t, len(results) == 2, fmt.Sprintf("expected a hand per spot, got %d", len(results)))
+	ExpectHandResult(t, results[0], core.MakeHandResult(core.HandResultLose, -3), "2/5/8/9")
+	ExpectHandResult(t, results[1], core.MakeHandResult(core.HandResultLose, -3), "3/6/T")
+
+	// a single spot plays the round as before
+	rules.TrackingStrategy = strategies.InitHighLow(map[int]strategies.BidStrategy{0: {Hands: 1, Units: 3}})
+	Check(t, len(PlayHand(core.GenerateShoe(1), rules)) == 1, "expected a single hand")
+}
+
+func Test_WongingObservesRounds(t *testing.T) {
+	// a flatbet strategy always sits at TC 0, so a player waiting for +1 never plays
+	rules := MakeTestRules().SetPenetration(0.5).SetWonging(1, 0)
+	res, err := PlayShoe(core.GenerateShoe(1).Shuffle(), rules, 100)
+	Check(t, err == nil, fmt.Sprintf("should have played the shoe out, got %v", err))
+	Check(t, res.Hands == 0, fmt.Sprintf("should not have played a hand, played %d", res.Hands))
+	Check(t, res.Observed > 0, "should have observed the shoe")
+	Check(t, res.EV == 0, "observed hands should not change the bankroll")
+	Check(t, len(res.RoundAVs) == res.Observed, "observed rounds should still take up table time")
+
+	rules.SetWonging(0, 0)
+	res, err = PlayShoe(core.GenerateShoe(1).Shuffle(), rules, 100)
+	Check(t, err == nil, fmt.Sprintf("should have played the shoe out, got %v", err))
+	Check(t, res.Observed == 0, fmt.Sprintf("should have played every hand, observed %d", res.Observed))
+	Check(t, res.Hands > 0, "should have played the shoe")
+}
+
+func Test_ObservedRoundHasNoSeat(t *testing.T) {
+	// the dealer's 5/T draws to 25, no player cards are dealt
+	deck := scriptedDeck(t, "5 T T")
+	results := PlaySeats(deck, MakeTestRules(), nil)
+	Check(t, len(results) == 0, fmt.Sprintf("expected no seats, got %d", len(results)))
+	Check(t, deck.Remaining() == 0, "the dealer should have played out their hand")
+}
+
+func Test_ContinuousShuffleCantBeCounted(t *testing.T) {
+	rules := MakeTestRules().SetPenetration(1.5).SetContinuousShuffle(10)
+	rules.TrackingStrategy = strategies.InitHighLow(map[int]strategies.BidStrategy{0: {Hands: 1, Units: 1}, 2: {Hands: 1, Units: 8}})
+	results := playTestGame(t, *rules, 20, 10000)
+	if results.Shuffles != 0 {
+		t.Fatalf("a continuous shuffler has no hand shuffles, got %d", results.Shuffles)
+	}
+	for tc := range results.BidsByTC {
+		if tc != 0 {
+			t.Fatalf("every bid should be made off a fresh count, got a bid at %d", tc)
+		}
+	}
+	if results.Hands < 20*40 {
+		t.Fatalf("expected shoes as long as dealt ones, got %d hands over 20 shoes", results.Hands)
+	}
+}
+
+func Test_ScriptedResplitRound(t *testing.T) {
+	// 88 v T/6. The first 8 draws another 8 and resplits, 8/3 doubles to 21 and
+	// 8/T stands. The second 8 draws a 9 and stands, the dealer busts drawing a 6
+	deck := scriptedDeck(t, "8 T 8 6 8 3 T T 9 6")
+	results := PlayHand(deck, MakeTestRules().SetMaxPlayerSplits(2))
+	Check(t, len(results) == 3, fmt.Sprintf("expected 3 hands, got %d", len(results)))
+	ExpectHandResult(t, results[0], core.MakeHandResult(core.HandResultWin, 2), "doubled 8/3/T")
+	ExpectHandResult(t, results[1], core.MakeHandResult(core.HandResultWin, 1), "8/T")
+	ExpectHandResult(t, results[2], core.MakeHandResult(core.HandResultWin, 1), "8/9")
+	Check(t, deck.Remaining() == 0, "every scripted card should have been dealt")
+
+	// without the resplit the 8/8 stands on 16
+	deck = scriptedDeck(t, "8 T 8 6 8 3 T T")
+	results = PlayHand(deck, MakeTestRules().SetMaxPlayerSplits(1))
+	Check(t, len(results) == 2, fmt.Sprintf("expected 2 hands, got %d", len(results)))
+	ExpectHandResult(t, results[0], core.MakeHandResult(core.HandResultWin, 1), "8/8")
+	ExpectHandResult(t, results[1], core.MakeHandResult(core.HandResultWin, 2), "doubled 8/3/T")
+}
+
+func Test_ScriptedShoeOutOfCards(t *testing.T) {
+	// the round needs four cards, the script runs out after two
+	_, err := PlayShoe(scriptedDeck(t, "8 T"), MakeTestRules(), 100)
+	Check(t, errors.Is(err, core.ErrOutOfCards), fmt.Sprintf("expected out of cards, got %v", err))
+}
+
+func Test_BankrollCarriesForKelly(t *testing.T) {
+	rules := MakeTestRules().SetPenetration(1.5).SetSeed(1)
+	rules.TrackingStrategy = strategies.InitHighLow(map[int]strategies.BidStrategy{0: {Hands: 1, Units: 1}, 2: {Hands: 1, Units: 8}})
+	res := playTestGame(t, *rules, 20, 1000)
+	Check(t, res.Result == 1000, fmt.Sprintf("a spread starts every shoe on the same bankroll, ended on %f", res.Result))
+
+	rules.TrackingStrategy = strategies.InitHighLowWithBidder(strategies.NewKellyBidder(1, 1, 0))
+	res = playTestGame(t, *rules, 20, 1000)
+	Check(t, res.Result == 1000+res.EV, fmt.Sprintf("kelly should carry its bankroll between shoes, ended on %f after %f", res.Result, res.EV))
+}
+
+func Test_InfiniteDeckGame(t *testing.T) {
+	rules := MakeTestRules().SetInfiniteDeck(true).SetSeed(1)
+	rules.TrackingStrategy = strategies.InitHighLow(map[int]strategies.BidStrategy{})
+	res := playTestGame(t, *rules, 20, 1000)
+	Check(t, res.Hands > 20*30, fmt.Sprintf("expected a shoe's worth of hands per shoe, got %d", res.Hands))
+	Check(t, res.Shuffles == 0, "an infinite deck is never shuffled")
+	Check(t, res.HighTC < 1 && res.LowTC > -1, fmt.Sprintf("the count can't move in an infinite deck, got %f to %f", res.LowTC, res.HighTC))
+}
+
+func Test_ZeroDeckDealsStandard(t *testing.T) {
+	rules := MakeTestRules().SetPenetration(1.5).SetDeck(core.DeckComposition{})
+	res := playTestGame(t, *rules, 5, 1000)
+	Check(t, res.Hands > 0, "rules without a deck should deal standard decks")
+	Check(t, rules.newShoe(6, 0).Remaining() == 6*core.DeckSize, "expected a 6 deck shoe of standard decks")
+}
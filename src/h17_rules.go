@@ -16,6 +16,8 @@ const (
 	PlayerActionDoubleOrStand
 	PlayerActionDoubleOrHit
 	PlayerActionSplit
+	PlayerActionSurrenderOrHit
+	PlayerActionSurrenderOrStand
 )
 
 type RuleV2 struct {
@@ -0,0 +1,130 @@
+package blackjack
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+)
+
+// HoleCardExposure models a sloppy dealer flashing the hole card. When the
+// player sees it they play the best decision against the dealer's known hand
+type HoleCardExposure struct {
+	Rate float64 // chance the hole card is seen in a round
+
+	Exposed  int
+	hole     core.Card
+	seen     bool
+	source   *rand.Rand
+	bestPlay map[holeCardKey]PlayerDecision
+}
+
+type holeCardKey struct {
+	decks     int
+	upcard    int
+	hole      int
+	total     int
+	soft      bool
+	pair      int
+	canDouble bool
+	canSplit  bool
+}
+
+func NewHoleCardExposure(rate float64) *HoleCardExposure {
+	return &HoleCardExposure{
+		Rate:     rate,
+		source:   rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+		bestPlay: map[holeCardKey]PlayerDecision{},
+	}
+}
+
+// Instance copies the model with its own source, seeded when seed isn't 0
+func (hc *HoleCardExposure) Instance(seed uint64) *HoleCardExposure {
+	if hc == nil {
+		return nil
+	}
+	created := NewHoleCardExposure(hc.Rate)
+	if seed != 0 {
+		// keep clear of the shoe, error model and camouflage seeds
+		created.source = rand.New(rand.NewPCG(seed^0x9e3779b97f4a7c15, ^seed))
+	}
+	return created
+}
+
+// Flash is called with the hole card once it's dealt, it returns whether the
+// player sees it this round
+func (hc *HoleCardExposure) Flash(hole core.Card) bool {
+	hc.hole = hole
+	hc.seen = hc.Rate > 0 && hc.source.Float64() < hc.Rate
+	if hc.seen {
+		hc.Exposed++
+	}
+	return hc.seen
+}
+
+// Hide ends the round, the next hole card hasn't been seen yet
+func (hc *HoleCardExposure) Hide() {
+	hc.seen = false
+}
+
+// Decide swaps the decision for the best play against the dealer's two cards
+// when the hole card was seen
+func (hc *HoleCardExposure) Decide(rules *BlackjackGameRules, hand core.Hand, dealerUpcard core.Card,
+	deck core.Shoe, decision PlayerDecision, splitCounter int) PlayerDecision {
+	total, soft := hand.HandValue()
+	if !hc.seen || decision == PlayerDecisionNatural21 || hand.SplitAcesHand || total >= 21 {
+		return decision
+	}
+	pair, isPair := hand.IsPair()
+	if !isPair || splitCounter >= rules.MaxPlayerSplits {
+		pair = 0
+	}
+	key := holeCardKey{
+		decks:     int(math.Round(float64(deck.Decks()))),
+		upcard:    dealerUpcard.Value,
+		hole:      hc.hole.Value,
+		total:     total,
+		soft:      soft,
+		pair:      pair,
+		canDouble: rules.canDouble(hand) && (!hand.SplitHand || rules.DoubleAfterSplit),
+		canSplit:  pair != 0,
+	}
+	if best, exists := hc.bestPlay[key]; exists {
+		return best
+	}
+	best := rules.holeCardDecision(hand, dealerUpcard, hc.hole, key)
+	hc.bestPlay[key] = best
+	return best
+}
+
+// holeCardDecision finds the decision with the best expectation against a
+// dealer starting from both cards, playing on with the ruleset afterwards
+func (rs *BlackjackGameRules) holeCardDecision(hand core.Hand, dealerUpcard core.Card, hole core.Card, key holeCardKey) PlayerDecision {
+	comp := rs.deck().Composition(key.decks).Remove(dealerUpcard.Value).Remove(hole.Value)
+	for _, c := range hand.Cards {
+		comp = comp.Remove(c.Value)
+	}
+	total, soft := addCard(0, false, dealerUpcard.Value)
+	total, soft = addCard(total, soft, hole.Value)
+	he := handExpectation{rules: rs, upcard: dealerUpcard, dealer: rs.dealerDraw(total, soft, comp)}
+
+	candidates := []PlayerDecision{PlayerDecisionStand, PlayerDecisionHit}
+	if key.canDouble {
+		candidates = append(candidates, PlayerDecisionDouble)
+	}
+	if key.canSplit {
+		if key.pair == 11 {
+			candidates = append(candidates, PlayerDecisionSplitAces)
+		} else {
+			candidates = append(candidates, PlayerDecisionSplit)
+		}
+	}
+	best := PlayerDecisionStand
+	bestEV := math.Inf(-1)
+	for _, decision := range candidates {
+		if ev := he.decision(hand, comp, 0, decision); ev > bestEV {
+			best, bestEV = decision, ev
+		}
+	}
+	return best
+}
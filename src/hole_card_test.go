@@ -0,0 +1,95 @@
+package blackjack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+)
+
+func TestHoleCardDecide(t *testing.T) {
+	rules := MakeTestRules()
+	deck := core.GenerateShoe(6)
+	hc := NewHoleCardExposure(1)
+	tests := []struct {
+		Hand     core.Hand
+		Upcard   int
+		Hole     int
+		Expected PlayerDecision
+	}{
+		{Hand: MakeHand(10, 6), Upcard: 10, Hole: 6, Expected: PlayerDecisionStand}, // dealer's stiff
+		{Hand: MakeHand(10, 2), Upcard: 7, Hole: 10, Expected: PlayerDecisionHit},   // dealer stands on 17
+		{Hand: MakeHand(10, 8), Upcard: 10, Hole: 10, Expected: PlayerDecisionHit},  // 18 loses to 20
+		{Hand: MakeHand(10, 7), Upcard: 10, Hole: 7, Expected: PlayerDecisionStand}, // 17 pushes
+		{Hand: MakeHand(9, 2), Upcard: 2, Hole: 4, Expected: PlayerDecisionDouble},  // dealer's 6
+		{Hand: MakeHand(8, 8), Upcard: 10, Hole: 10, Expected: PlayerDecisionHit},   // 16 vs 20, a split 8 won't beat it
+	}
+	for _, test := range tests {
+		upcard := core.Card{Value: test.Upcard}
+		hc.Flash(core.Card{Value: test.Hole})
+		if d := hc.Decide(rules, test.Hand, upcard, deck, PlayerDecisionStand, 0); d != test.Expected {
+			t.Errorf("%s vs %d/%d should %s, got %s", test.Hand.ToString(), test.Upcard, test.Hole,
+				test.Expected.ToString(), d.ToString())
+		}
+	}
+	hc.Hide()
+	if d := hc.Decide(rules, MakeHand(10, 6), core.Card{Value: 10}, deck, PlayerDecisionHit, 0); d != PlayerDecisionHit {
+		t.Fatalf("hidden hole cards should leave the decision alone, got %s", d.ToString())
+	}
+	if hc.Exposed != len(tests) {
+		t.Fatalf("expected %d exposed hole cards, got %d", len(tests), hc.Exposed)
+	}
+}
+
+func TestHoleCardCounted(t *testing.T) {
+	previewed := func(rate float64) []int {
+		rules := MakeTestRules().SetHoleCardExposure(NewHoleCardExposure(rate))
+		// 2/3 vs a 9 with a 5 in the hole
+		deck, err := core.NewScriptedDeck("2 5 3 9 T T T T")
+		if err != nil {
+			t.Fatal(err)
+		}
+		values := []int{}
+		deck.PreviewCard = func(c core.Card) {
+			values = append(values, c.Value)
+		}
+		PlayRound(deck, rules, 1)
+		return values
+	}
+
+	// a hidden hole card is only counted once the player's done and it's turned over
+	hidden := previewed(0)
+	expected := []int{2, 3, 9, 10, 10, 5}
+	if fmt.Sprint(hidden) != fmt.Sprint(expected) {
+		t.Fatalf("expected the hole card counted last, got %v", hidden)
+	}
+	// a flashed one is counted as soon as it's seen, and only the once
+	flashed := previewed(1)
+	if len(flashed) < 4 || flashed[3] != 5 {
+		t.Fatalf("expected the flashed hole card counted after the upcard, got %v", flashed)
+	}
+	fives := 0
+	for _, v := range flashed {
+		if v == 5 {
+			fives++
+		}
+	}
+	if fives != 1 {
+		t.Fatalf("expected the hole card counted once, got %v", flashed)
+	}
+}
+
+func TestHoleCardSeed(t *testing.T) {
+	// the flashes shouldn't follow the camouflage's draws off the same seed
+	hc := NewHoleCardExposure(0.5).Instance(1)
+	camouflage := NewCamouflage().Instance(1)
+	matched := 0
+	for i := 0; i < 64; i++ {
+		if hc.Flash(core.Card{Value: 10}) == (camouflage.source.Float64() < 0.5) {
+			matched++
+		}
+	}
+	if matched == 64 {
+		t.Fatalf("the hole card and camouflage draws come from the same stream")
+	}
+}
@@ -0,0 +1,169 @@
+package blackjack
+
+import (
+	"math"
+	"sort"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+)
+
+// buckets need this many samples to count towards the crossover
+const minIndexSamples = 200
+
+// SimulatePlays plays through shoes under the rules' tracking strategy and,
+// before every round, plays each of the plays out both ways from the unseen
+// cards. Returns the gain of each play's action over its alternative per unit
+// bet, bucketed by the playing true count. Errors when the shoe runs out of cards
+func SimulatePlays(rules BlackjackGameRules, decks int, shoes int, plays []Play) (gains []map[int]TCResult, err error) {
+	defer recoverOutOfCards(&err)
+	deck := rules.newShoe(decks, rules.Seed)
+	rules.TrackingStrategy = rules.instanceStrategy()
+	// indices are found off perfect play with the hole card hidden
+	rules.Errors = nil
+	rules.HoleCard = nil
+	deck.PreviewCard = func(c core.Card) {
+		rules.TrackingStrategy.Update(c)
+	}
+
+	gains = make([]map[int]TCResult, len(plays))
+	for i := range gains {
+		gains[i] = map[int]TCResult{}
+	}
+	for i := 0; i < shoes; i++ {
+		for !rules.shoeFinished(deck) {
+			tc := rules.playingTrueCount(deck)
+			for p, play := range plays {
+				if gain, ok := rules.playGain(play, deck.Unseen()); ok {
+					result := gains[p][tc]
+					result.Add(gain)
+					gains[p][tc] = result
+				}
+			}
+			PlayRound(deck, &rules, 1)
+			rules.endRound(deck)
+		}
+		rules.TrackingStrategy.Shuffle()
+		deck.Shuffle()
+	}
+	return gains, nil
+}
+
+// playGain deals the play's cards out of the unseen cards and plays the action
+// and the alternative off the same remaining cards. Rounds where the dealer has
+// a natural are thrown out, apart from insurance which is decided by them
+func (rs *BlackjackGameRules) playGain(play Play, unseen *core.Deck) (float32, bool) {
+	upcard, ok := unseen.Take(play.DealerUpcard)
+	if !ok {
+		return 0, false
+	}
+	hand := core.Hand{}
+	for _, v := range play.PlayerCards {
+		c, ok := unseen.Take(v)
+		if !ok {
+			return 0, false
+		}
+		hand.Cards = append(hand.Cards, c)
+	}
+	dealer := core.Hand{Cards: []core.Card{unseen.Deal(), upcard}}
+	if play.Insurance {
+		if dealer.Cards[0].Value == 10 {
+			return 2, true
+		}
+		return -1, true
+	}
+	if value, _ := dealer.HandValue(); value == 21 {
+		return 0, false
+	}
+
+	action := rs.playDecision(hand, dealer, unseen.Clone(), play.Action)
+	alternative := rs.playDecision(hand, dealer, unseen, play.Alternative)
+	return action - alternative, true
+}
+
+// playDecision forces the first decision on the hand, plays the rest out and
+// returns the result of a 1 unit bet
+func (rs *BlackjackGameRules) playDecision(hand core.Hand, dealer core.Hand, deck *core.Deck, decision PlayerDecision) float32 {
+	upcard := dealer.Cards[1]
+	splitCounter := 0
+	hands := []core.Hand{hand}
+	switch decision {
+	case PlayerDecisionHit:
+		hand.Cards = append(hand.Cards, deck.Deal())
+		if value, _ := hand.HandValue(); value < 21 {
+			hands = rs.PlayPlayerHand(hand, upcard, deck, 1, &splitCounter)
+		} else {
+			hands = []core.Hand{hand}
+		}
+	case PlayerDecisionDouble:
+		hand.Cards = append(hand.Cards, deck.Deal())
+		hand.Doubled = true
+		hands = []core.Hand{hand}
+	case PlayerDecisionSplit, PlayerDecisionSplitAces:
+		splitCounter++
+		aces := decision == PlayerDecisionSplitAces
+		hands = []core.Hand{}
+		for _, c := range hand.Cards {
+			split := core.Hand{Cards: []core.Card{c, deck.Deal()}, SplitHand: !aces, SplitAcesHand: aces}
+			hands = append(hands, rs.PlayPlayerHand(split, upcard, deck, 1, &splitCounter)...)
+		}
+	}
+
+	allBusted := true
+	for _, h := range hands {
+		if value, _ := h.HandValue(); value <= 21 && !h.Surrendered {
+			allBusted = false
+		}
+	}
+	if !allBusted {
+		dealer = rs.PlayDealerHand(dealer, deck)
+	}
+	result := float32(0)
+	for _, h := range hands {
+		result += rs.HandResult(h, dealer, 1).AV
+	}
+	return result
+}
+
+// FindIndex fits a line to the per count gains and returns the index where the
+// play's action starts to beat its alternative. False when the gain doesn't
+// change with the count
+func FindIndex(play Play, gains map[int]TCResult) (Index, bool) {
+	counts := make([]int, 0, len(gains))
+	for tc, g := range gains {
+		if g.Rounds >= minIndexSamples {
+			counts = append(counts, tc)
+		}
+	}
+	if len(counts) < 2 {
+		return Index{}, false
+	}
+	sort.Ints(counts)
+
+	// weighted least squares of the mean gain on the count
+	n, sumX, sumY, sumXX, sumXY := float64(0), float64(0), float64(0), float64(0), float64(0)
+	for _, tc := range counts {
+		g := gains[tc]
+		w := float64(g.Rounds)
+		x := float64(tc)
+		y := g.EV / w
+		n += w
+		sumX += w * x
+		sumY += w * y
+		sumXX += w * x * x
+		sumXY += w * x * y
+	}
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	if slope == 0 || math.IsNaN(slope) {
+		return Index{}, false
+	}
+	intercept := (sumY - slope*sumX) / n
+	crossover := -intercept / slope
+
+	idx := Index{Play: play.Name, AtOrAbove: slope > 0}
+	if idx.AtOrAbove {
+		idx.TrueCount = int(math.Ceil(crossover))
+	} else {
+		idx.TrueCount = int(math.Floor(crossover))
+	}
+	return idx, true
+}
@@ -0,0 +1,95 @@
+package blackjack
+
+import (
+	"math/rand/v2"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/strategies"
+)
+
+// ErrorModel makes the player imperfect, rates are per decision and per card seen
+type ErrorModel struct {
+	ActionErrorRate float64 // chance a playing decision is swapped for another legal one
+	MiscountRate    float64 // chance a card is miscounted by +/-1
+	TCNoise         float64 // standard deviation of the true count estimate
+
+	ActionErrors int
+	Miscounts    int
+	source       *rand.Rand
+}
+
+func NewErrorModel(actionErrorRate, miscountRate, tcNoise float64) *ErrorModel {
+	return &ErrorModel{
+		ActionErrorRate: actionErrorRate,
+		MiscountRate:    miscountRate,
+		TCNoise:         tcNoise,
+		source:          rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+}
+
+// Instance copies the model with its own source, seeded when seed isn't 0
+func (em *ErrorModel) Instance(seed uint64) *ErrorModel {
+	if em == nil {
+		return nil
+	}
+	created := NewErrorModel(em.ActionErrorRate, em.MiscountRate, em.TCNoise)
+	if seed != 0 {
+		// keep clear of the shoe's seed so the errors don't follow the shuffles
+		created.source = rand.New(rand.NewPCG(seed, ^seed))
+	}
+	return created
+}
+
+// Attach hooks the true count noise into the strategy when it estimates one
+func (em *ErrorModel) Attach(strategy strategies.TrackingStrategy) {
+	if estimator, ok := strategy.(strategies.TrueCountEstimator); ok && em.TCNoise > 0 {
+		estimator.SetTrueCountNoise(func() float32 {
+			return float32(em.source.NormFloat64() * em.TCNoise)
+		})
+	}
+}
+
+// Count is called for every card seen after the strategy counted it
+func (em *ErrorModel) Count(strategy strategies.TrackingStrategy) {
+	if em.MiscountRate <= 0 || em.source.Float64() >= em.MiscountRate {
+		return
+	}
+	if counter, ok := strategy.(strategies.Miscountable); ok {
+		delta := 1
+		if em.source.IntN(2) == 0 {
+			delta = -1
+		}
+		counter.Miscount(delta)
+		em.Miscounts++
+	}
+}
+
+// Decide swaps the decision for a random other legal one at the error rate
+func (em *ErrorModel) Decide(rules *BlackjackGameRules, hand core.Hand, decision PlayerDecision, splitCounter int) PlayerDecision {
+	if value, _ := hand.HandValue(); value >= 21 || hand.SplitAcesHand || em.ActionErrorRate <= 0 ||
+		em.source.Float64() >= em.ActionErrorRate {
+		return decision
+	}
+	legal := make([]PlayerDecision, 0, 5)
+	for _, d := range []PlayerDecision{PlayerDecisionStand, PlayerDecisionHit} {
+		if d != decision {
+			legal = append(legal, d)
+		}
+	}
+	if decision != PlayerDecisionDouble && rules.canDouble(hand) && (!hand.SplitHand || rules.DoubleAfterSplit) {
+		legal = append(legal, PlayerDecisionDouble)
+	}
+	if decision != PlayerDecisionSurrender && rules.canSurrender(hand) {
+		legal = append(legal, PlayerDecisionSurrender)
+	}
+	if val, pair := hand.IsPair(); pair && splitCounter < rules.MaxPlayerSplits &&
+		decision != PlayerDecisionSplit && decision != PlayerDecisionSplitAces {
+		if val == 11 {
+			legal = append(legal, PlayerDecisionSplitAces)
+		} else {
+			legal = append(legal, PlayerDecisionSplit)
+		}
+	}
+	em.ActionErrors++
+	return legal[em.source.IntN(len(legal))]
+}
@@ -0,0 +1,106 @@
+package blackjack
+
+import (
+	"testing"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/strategies"
+)
+
+func TestErrorModelDecide(t *testing.T) {
+	rules := MakeTestRules()
+	errors := NewErrorModel(1, 0, 0)
+	for i := 0; i < 100; i++ {
+		d := errors.Decide(rules, MakeHand(10, 6), PlayerDecisionHit, 0)
+		if d == PlayerDecisionHit || d == PlayerDecisionSplit {
+			t.Fatalf("expected a different legal decision, got %s", d.ToString())
+		}
+		if d := errors.Decide(rules, MakeHand(10, 4, 2), PlayerDecisionHit, 0); d != PlayerDecisionStand {
+			t.Fatalf("can only stand instead of hitting a 3 card hand, got %s", d.ToString())
+		}
+	}
+	if errors.ActionErrors != 200 {
+		t.Fatalf("expected 200 action errors, got %d", errors.ActionErrors)
+	}
+	if d := errors.Decide(rules, MakeHand(10, 11), PlayerDecisionNatural21, 0); d != PlayerDecisionNatural21 {
+		t.Fatalf("naturals can't be misplayed")
+	}
+}
+
+func TestErrorModelSurrender(t *testing.T) {
+	rules := MakeTestRules().SetLateSurrender(true)
+	errors := NewErrorModel(1, 0, 0)
+	surrendered := false
+	for i := 0; i < 100; i++ {
+		surrendered = surrendered || errors.Decide(rules, MakeHand(10, 6), PlayerDecisionHit, 0) == PlayerDecisionSurrender
+		if d := errors.Decide(rules, MakeHand(10, 4, 2), PlayerDecisionHit, 0); d == PlayerDecisionSurrender {
+			t.Fatalf("can't surrender a 3 card hand")
+		}
+	}
+	if !surrendered {
+		t.Fatalf("expected surrender among the errors where it's allowed")
+	}
+	if d := errors.Decide(rules, MakeHand(10, 6), PlayerDecisionSurrender, 0); d == PlayerDecisionSurrender {
+		t.Fatalf("expected a different decision than surrender")
+	}
+}
+
+func TestErrorModelTrueCountNoise(t *testing.T) {
+	errors := NewErrorModel(0, 0, 2)
+	strat := strategies.InitHighLow(map[int]strategies.BidStrategy{})
+	errors.Attach(strat)
+	deck := core.GenerateShoe(6)
+	first := strat.PlayingTrueCount(deck)
+	if strat.PlayingTrueCount(deck) != first {
+		t.Fatalf("the same cards should give the same estimate")
+	}
+	strat.Update(core.Card{Value: 8})
+	if strat.PlayingTrueCount(deck) == first {
+		t.Fatalf("seeing a card should draw a new estimate")
+	}
+}
+
+func TestErrorModelMiscount(t *testing.T) {
+	errors := NewErrorModel(0, 1, 0)
+	strat := strategies.InitHighLow(map[int]strategies.BidStrategy{})
+	for i := 0; i < 10; i++ {
+		strat.Update(core.Card{Value: 8})
+		errors.Count(strat)
+	}
+	if errors.Miscounts != 10 {
+		t.Fatalf("expected every card to be miscounted, got %d", errors.Miscounts)
+	}
+	if strat.RunningCount%2 != 0 {
+		t.Fatalf("10 +/-1 miscounts should leave an even count, got %d", strat.RunningCount)
+	}
+}
+
+func TestSeededGamesRepeat(t *testing.T) {
+	rules := MakeTestRules().SetPenetration(1.5).SetSeed(7)
+	rules.TrackingStrategy = strategies.InitHighLow(map[int]strategies.BidStrategy{0: {Hands: 1, Units: 1}, 2: {Hands: 1, Units: 4}})
+	a := playTestGame(t, *rules, 50, 10000)
+	b := playTestGame(t, *rules, 50, 10000)
+	if a.EV != b.EV || a.Hands != b.Hands {
+		t.Fatalf("seeded games should repeat, got %f and %f", a.EV, b.EV)
+	}
+
+	rules.SetErrorModel(NewErrorModel(0.05, 0.05, 0.5))
+	c := playTestGame(t, *rules, 50, 10000)
+	if c.ActionErrors == 0 || c.Miscounts == 0 {
+		t.Fatalf("expected errors to be made, got %d action errors and %d miscounts", c.ActionErrors, c.Miscounts)
+	}
+}
+
+func TestErrorModelDoubleAnyCards(t *testing.T) {
+	em := NewErrorModel(1, 0, 0).Instance(1)
+	rules := NewSpanish21Game()
+	doubled := false
+	for i := 0; i < 100; i++ {
+		if em.Decide(rules, MakeHand(2, 4, 4), PlayerDecisionHit, 0) == PlayerDecisionDouble {
+			doubled = true
+		}
+	}
+	if !doubled {
+		t.Fatalf("a 3 card hand can be doubled in error when any cards can be doubled")
+	}
+}
@@ -0,0 +1,55 @@
+package blackjack
+
+import "github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+
+// Play is a single playing decision, the player's first cards vs the dealer
+// upcard. Action is the count based deviation and Alternative the basic
+// strategy play it's measured against
+type Play struct {
+	Name         string
+	PlayerCards  []int
+	DealerUpcard int
+	Action       PlayerDecision
+	Alternative  PlayerDecision
+	Insurance    bool // insurance is measured per unit insured, player cards are ignored
+}
+
+// The illustrious 18 plus the insurance decision
+var IndexPlays = []Play{
+	{Name: "insurance", DealerUpcard: 11, Insurance: true},
+	{Name: "16v10", PlayerCards: []int{10, 6}, DealerUpcard: 10, Action: PlayerDecisionStand, Alternative: PlayerDecisionHit},
+	{Name: "15v10", PlayerCards: []int{10, 5}, DealerUpcard: 10, Action: PlayerDecisionStand, Alternative: PlayerDecisionHit},
+	{Name: "TTv5", PlayerCards: []int{10, 10}, DealerUpcard: 5, Action: PlayerDecisionSplit, Alternative: PlayerDecisionStand},
+	{Name: "TTv6", PlayerCards: []int{10, 10}, DealerUpcard: 6, Action: PlayerDecisionSplit, Alternative: PlayerDecisionStand},
+	{Name: "10v10", PlayerCards: []int{6, 4}, DealerUpcard: 10, Action: PlayerDecisionDouble, Alternative: PlayerDecisionHit},
+	{Name: "12v3", PlayerCards: []int{10, 2}, DealerUpcard: 3, Action: PlayerDecisionStand, Alternative: PlayerDecisionHit},
+	{Name: "12v2", PlayerCards: []int{10, 2}, DealerUpcard: 2, Action: PlayerDecisionStand, Alternative: PlayerDecisionHit},
+	{Name: "11vA", PlayerCards: []int{6, 5}, DealerUpcard: 11, Action: PlayerDecisionDouble, Alternative: PlayerDecisionHit},
+	{Name: "9v2", PlayerCards: []int{5, 4}, DealerUpcard: 2, Action: PlayerDecisionDouble, Alternative: PlayerDecisionHit},
+	{Name: "10vA", PlayerCards: []int{6, 4}, DealerUpcard: 11, Action: PlayerDecisionDouble, Alternative: PlayerDecisionHit},
+	{Name: "9v7", PlayerCards: []int{5, 4}, DealerUpcard: 7, Action: PlayerDecisionDouble, Alternative: PlayerDecisionHit},
+	{Name: "16v9", PlayerCards: []int{10, 6}, DealerUpcard: 9, Action: PlayerDecisionStand, Alternative: PlayerDecisionHit},
+	{Name: "13v2", PlayerCards: []int{10, 3}, DealerUpcard: 2, Action: PlayerDecisionHit, Alternative: PlayerDecisionStand},
+	{Name: "12v4", PlayerCards: []int{10, 2}, DealerUpcard: 4, Action: PlayerDecisionHit, Alternative: PlayerDecisionStand},
+	{Name: "12v5", PlayerCards: []int{10, 2}, DealerUpcard: 5, Action: PlayerDecisionHit, Alternative: PlayerDecisionStand},
+	{Name: "12v6", PlayerCards: []int{10, 2}, DealerUpcard: 6, Action: PlayerDecisionHit, Alternative: PlayerDecisionStand},
+	{Name: "13v3", PlayerCards: []int{10, 3}, DealerUpcard: 3, Action: PlayerDecisionHit, Alternative: PlayerDecisionStand},
+}
+
+// PlayValue is the gain of the play's action over its alternative, per unit
+// bet, given the dealer has peeked and doesn't have a natural. For insurance it's
+// the expectation of the insurance bet
+func (rs *BlackjackGameRules) PlayValue(play Play, comp core.Composition) float64 {
+	hand, remaining := initialDeal(play.PlayerCards, play.DealerUpcard, comp)
+	if play.Insurance {
+		// pays 2:1 when the hole card is a ten
+		p := remaining.Probability(10)
+		return 2*p - (1 - p)
+	}
+	he := handExpectation{
+		rules:  rs,
+		upcard: core.Card{Value: play.DealerUpcard},
+		dealer: rs.dealerOutcomes(play.DealerUpcard, remaining),
+	}
+	return he.decision(hand, remaining, 0, play.Action) - he.decision(hand, remaining, 0, play.Alternative)
+}
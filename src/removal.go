@@ -0,0 +1,52 @@
+package blackjack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+)
+
+// EffectsOfRemoval is the change in expectation from removing a single card of
+// each value from the shoe, indexed by value like core.Composition
+type EffectsOfRemoval [12]float64
+
+func removalEffects(comp core.Composition, f func(core.Composition) float64) EffectsOfRemoval {
+	effects := EffectsOfRemoval{}
+	base := f(comp)
+	for value := 2; value <= 11; value++ {
+		effects[value] = f(comp.Remove(value)) - base
+	}
+	return effects
+}
+
+// EffectsOfRemoval on the overall expectation of a round played off the composition
+func (rs *BlackjackGameRules) EffectsOfRemoval(comp core.Composition) EffectsOfRemoval {
+	return removalEffects(comp, rs.ExpectedValue)
+}
+
+// PlayEffectsOfRemoval on the gain of the play's action over its alternative
+func (rs *BlackjackGameRules) PlayEffectsOfRemoval(play Play, comp core.Composition) EffectsOfRemoval {
+	return removalEffects(comp, func(c core.Composition) float64 {
+		return rs.PlayValue(play, c)
+	})
+}
+
+// FindPlay looks up one of the IndexPlays by name, e.g. 16v10 or insurance
+func FindPlay(name string) (Play, error) {
+	for _, play := range IndexPlays {
+		if strings.EqualFold(play.Name, name) {
+			return play, nil
+		}
+	}
+	return Play{}, fmt.Errorf("unknown play %s", name)
+}
+
+// ToString formats the effects as percentages, 2 through A
+func (eor EffectsOfRemoval) ToString() string {
+	s := make([]string, 0, 10)
+	for value := 2; value <= 11; value++ {
+		s = append(s, fmt.Sprintf("%+.4f%%", eor[value]*100))
+	}
+	return strings.Join(s, " ")
+}
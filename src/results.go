@@ -1,11 +1,19 @@
 package blackjack
 
+import "github.com/onemorebsmith/blackjack-solver/src/blackjack/strategies"
+
 type GameResults struct {
 	Hands            int
+	Observed         int // rounds watched without a bet while wonging
 	Wins             int
 	Losses           int
 	Pushes           int
 	Blackjacks       int
+	Surrenders       int
+	Rescues          int // doubled hands surrendered
+	Bonuses          int // 21s paid a bonus
+	Ruins            int
+	Shuffles         int // hand shuffles, a continuous shuffler never stops the game
 	EV               float32
 	Result           float32
 	AvgTC            float32
@@ -14,25 +22,99 @@ type GameResults struct {
 	EVVariance       float32
 	HourlyEVVariance float32
 	BidsByTC         map[int]int
+	AceAdjustedBids  int // bids moved to a different count by the ace side count
+	ActionErrors     int // decisions the error model changed
+	Miscounts        int
+	CamouflagedBids  int // bets changed by the camouflage rules
+	ExposedHoleCards int // rounds the dealer flashed the hole card
+	KeyCards         int // key cards an ace sequencer spotted
+	SequencedBids    int // rounds bet on a sequenced ace
+	SequencedAces    int // aces that came out shortly after their key card
+	TCResults        map[int]TCResult
 	HandAVs          []float32
+	RoundAVs         []float32 // every round including observed ones, only kept when wonging
+	HourlyAVs        []float32 // the result of each full hour of play
+}
+
+// Rounds is every round the player sat through, played or not
+func (r GameResults) Rounds() int {
+	return r.Hands + r.Observed
+}
+
+// TCResult accumulates round outcomes placed at a single true count
+type TCResult struct {
+	Rounds    int
+	EV        float64
+	EVSquared float64
+}
+
+func (r *TCResult) Add(av float32) {
+	r.Rounds++
+	r.EV += float64(av)
+	r.EVSquared += float64(av) * float64(av)
+}
+
+// TCEstimates converts the accumulated per-TC results into per-round estimates.
+// Results are in units bet, so these are only per-unit figures for a flatbet calibration run
+func (r GameResults) TCEstimates() map[int]strategies.TCEstimate {
+	total := 0
+	for _, v := range r.TCResults {
+		total += v.Rounds
+	}
+	estimates := make(map[int]strategies.TCEstimate, len(r.TCResults))
+	for tc, v := range r.TCResults {
+		if v.Rounds == 0 {
+			continue
+		}
+		mean := v.EV / float64(v.Rounds)
+		estimates[tc] = strategies.TCEstimate{
+			Frequency: float64(v.Rounds) / float64(total),
+			EV:        mean,
+			Variance:  v.EVSquared/float64(v.Rounds) - mean*mean,
+		}
+	}
+	return estimates
 }
 
 func AggregateResults(results ...GameResults) GameResults {
-	aggregated := GameResults{BidsByTC: map[int]int{}}
+	aggregated := GameResults{BidsByTC: map[int]int{}, TCResults: map[int]TCResult{}}
 	for _, r := range results {
 		aggregated.EV += r.EV
 		aggregated.Hands += r.Hands
+		aggregated.Observed += r.Observed
 		aggregated.Blackjacks += r.Blackjacks
+		aggregated.Surrenders += r.Surrenders
+		aggregated.Rescues += r.Rescues
+		aggregated.Bonuses += r.Bonuses
+		aggregated.Ruins += r.Ruins
+		aggregated.Shuffles += r.Shuffles
+		aggregated.ExposedHoleCards += r.ExposedHoleCards
+		aggregated.KeyCards += r.KeyCards
+		aggregated.SequencedBids += r.SequencedBids
+		aggregated.SequencedAces += r.SequencedAces
 		aggregated.Wins += r.Wins
 		aggregated.Losses += r.Losses
 		aggregated.Pushes += r.Pushes
 		aggregated.AvgTC += r.AvgTC
 		aggregated.HighTC += r.HighTC
 		aggregated.LowTC += r.LowTC
+		aggregated.AceAdjustedBids += r.AceAdjustedBids
+		aggregated.ActionErrors += r.ActionErrors
+		aggregated.Miscounts += r.Miscounts
+		aggregated.CamouflagedBids += r.CamouflagedBids
+
+		aggregated.HourlyAVs = append(aggregated.HourlyAVs, r.HourlyAVs...)
 
 		for tc, freq := range r.BidsByTC {
 			aggregated.BidsByTC[tc] += freq
 		}
+		for tc, res := range r.TCResults {
+			agg := aggregated.TCResults[tc]
+			agg.Rounds += res.Rounds
+			agg.EV += res.EV
+			agg.EVSquared += res.EVSquared
+			aggregated.TCResults[tc] = agg
+		}
 	}
 	return aggregated
 }
@@ -35,8 +35,9 @@ type RuleMap map[int64]Rule
 type SplitMap map[int64]struct{}
 
 type Ruleset struct {
-	rules RuleMap
-	spits SplitMap
+	rules   RuleMap
+	spits   SplitMap
+	rescues SplitMap // doubled totals given up by upcard, with a double rescue
 }
 
 func (r Rule) Hash() int64 {
@@ -57,6 +58,7 @@ const (
 	PlayerDecisionDouble
 	PlayerDecisionSplit
 	PlayerDecisionSplitAces
+	PlayerDecisionSurrender
 )
 
 func (d PlayerDecision) ToString() string {
@@ -73,12 +75,18 @@ func (d PlayerDecision) ToString() string {
 		return `split`
 	case PlayerDecisionSplitAces:
 		return `split aces`
+	case PlayerDecisionSurrender:
+		return `surrender`
 	}
 	return `unknown`
 }
 
 func (rs *BlackjackGameRules) MakeDealerDecision(dealerCards core.Hand) PlayerDecision {
 	value, soft := dealerCards.HandValue()
+	return rs.dealerDecision(value, soft)
+}
+
+func (rs *BlackjackGameRules) dealerDecision(value int, soft bool) PlayerDecision {
 	if value > 17 {
 		return PlayerDecisionStand
 	} else if value == 17 {
@@ -127,7 +135,7 @@ func (rs *BlackjackGameRules) MakePlayerDecision(playerCards core.Hand, dealerUp
 		Soft:         soft,
 	}
 
-	canDouble := playerCards.CanDouble()
+	canDouble := rs.canDouble(playerCards)
 	if rule, exists := rs.playerStrategy.rules[rule.Hash()]; exists {
 		switch rule.Action {
 		case PlayerActionDoubleOrHit:
@@ -140,6 +148,16 @@ func (rs *BlackjackGameRules) MakePlayerDecision(playerCards core.Hand, dealerUp
 				return PlayerDecisionDouble
 			}
 			return PlayerDecisionStand
+		case PlayerActionSurrenderOrHit:
+			if rs.canSurrender(playerCards) {
+				return PlayerDecisionSurrender
+			}
+			return PlayerDecisionHit
+		case PlayerActionSurrenderOrStand:
+			if rs.canSurrender(playerCards) {
+				return PlayerDecisionSurrender
+			}
+			return PlayerDecisionStand
 		case PlayerActionHit:
 			return PlayerDecisionHit
 		case PlayerActionStand:
@@ -173,7 +191,7 @@ func InitGame(rules RulesMap, splits []SplitRule) *Ruleset {
 		}
 	}
 
-	for dealerCard, rule := range H17Rules {
+	for dealerCard, rule := range rules {
 		for soft, rules := range rule.Actions {
 			for playerTotal, action := range rules {
 				created := Rule{
@@ -0,0 +1,83 @@
+package blackjack
+
+import "github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+
+// SessionParams limits each session, stop loss and win goal are in units with 0 for none
+type SessionParams struct {
+	Hours    float32
+	StopLoss float32
+	WinGoal  float32
+}
+
+type SessionResults struct {
+	Results    []float64 // each session's net result in units
+	Rounds     int
+	StopLosses int // sessions ended early by the stop loss
+	WinGoals   int // sessions ended early by reaching the win goal
+}
+
+func AggregateSessionResults(results ...SessionResults) SessionResults {
+	aggregated := SessionResults{}
+	for _, r := range results {
+		aggregated.Results = append(aggregated.Results, r.Results...)
+		aggregated.Rounds += r.Rounds
+		aggregated.StopLosses += r.StopLosses
+		aggregated.WinGoals += r.WinGoals
+	}
+	return aggregated
+}
+
+// PlaySessions plays each session from a fresh shoe, through as many shoes as
+// it takes to fill the hours or hit one of the limits
+func PlaySessions(rules BlackjackGameRules, decks int, sessions int, params SessionParams, roundsPerHour float32, bankrole float32) (SessionResults, error) {
+	deck := rules.dealer(decks, rules.Seed)
+	rules.TrackingStrategy = rules.instanceStrategy()
+	rules.Errors = rules.Errors.Instance(rules.Seed)
+	rules.Camouflage = rules.Camouflage.Instance(rules.Seed)
+	rules.HoleCard = rules.HoleCard.Instance(rules.Seed)
+	if rules.Errors != nil {
+		rules.Errors.Attach(rules.TrackingStrategy)
+	}
+	deck.SetPreviewCard(func(c core.Card) {
+		rules.TrackingStrategy.Update(c)
+		if rules.Errors != nil {
+			rules.Errors.Count(rules.TrackingStrategy)
+		}
+	})
+
+	sessionRounds := int(params.Hours * roundsPerHour)
+	results := SessionResults{Results: make([]float64, 0, sessions)}
+	for i := 0; i < sessions; i++ {
+		rounds := 0
+		net := float32(0)
+		stoppedLoss, reachedGoal := false, false
+		stop := func(shoeRounds int, shoeNet float32) bool {
+			total := net + shoeNet
+			stoppedLoss = params.StopLoss > 0 && total <= -params.StopLoss
+			reachedGoal = params.WinGoal > 0 && total >= params.WinGoal
+			return rounds+shoeRounds >= sessionRounds || stoppedLoss || reachedGoal
+		}
+		for rounds < sessionRounds && !stoppedLoss && !reachedGoal {
+			rules.TrackingStrategy.Shuffle()
+			deck.Reshuffle()
+			shoe, err := playShoeUntil(deck, &rules, bankrole+net, stop)
+			if err != nil {
+				return results, err
+			}
+			rounds += shoe.Rounds()
+			net += shoe.EV
+			if bankrole+net <= 0 {
+				// busted out of the bankroll, the session is over
+				break
+			}
+		}
+		results.Results = append(results.Results, float64(net))
+		results.Rounds += rounds
+		if stoppedLoss {
+			results.StopLosses++
+		} else if reachedGoal {
+			results.WinGoals++
+		}
+	}
+	return results, nil
+}
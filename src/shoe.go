@@ -0,0 +1,83 @@
+package blackjack
+
+import (
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/strategies"
+)
+
+// deck is the composition of each deck the rules deal. Rules built without
+// NewBlackjackGameRules have a zero Deck, which deals standard decks
+func (rs *BlackjackGameRules) deck() core.DeckComposition {
+	if rs.Deck == (core.DeckComposition{}) {
+		return core.StandardDeck
+	}
+	return rs.Deck
+}
+
+// newShoe builds and shuffles the shoe the rules are dealt from, seeded when seed isn't 0
+func (rs *BlackjackGameRules) newShoe(decks int, seed uint64) *core.Deck {
+	deck := core.GenerateShoeOf(decks, rs.deck())
+	if seed != 0 {
+		deck.Seed(seed)
+	}
+	if rs.ContinuousShuffle {
+		deck.SetContinuousShuffle(rs.CSMReservoir)
+	}
+	deck.SetCutCard(rs.cutCard())
+	deck.SetBurn(rs.BurnCards, rs.BurnVisible)
+	// new decks are washed before they're put through the casino's shuffle
+	return deck.Shuffle().SetShuffler(rs.Shuffler)
+}
+
+// dealer is what PlayGame deals from, an infinite deck or a shoe. An infinite
+// deck takes a shoe's worth of cards down to the cut card between shuffles
+func (rs *BlackjackGameRules) dealer(decks int, seed uint64) core.Shoe {
+	if !rs.InfiniteDeck {
+		return rs.newShoe(decks, seed)
+	}
+	cut := rs.cutCard()
+	perDeck := float32(rs.deck().Size())
+	shoeSize := float32(decks)*perDeck - cut.Penetration*perDeck
+	if cut.PercentDealt {
+		shoeSize = float32(decks) * perDeck * cut.Penetration
+	}
+	deck := core.NewInfiniteDeckOf(int(shoeSize), rs.deck())
+	if seed != 0 {
+		deck.Seed(seed)
+	}
+	return deck
+}
+
+// shoeFinished is true once the shoe is dealt down to the cut card. A continuous
+// shuffler never runs out, its shoes end after as many cards as a dealt shoe
+func (rs *BlackjackGameRules) shoeFinished(deck core.Shoe) bool {
+	return deck.ShoeFinished()
+}
+
+// endRound finishes the round on the shoe. The count can't follow discards back
+// into a continuous shuffler
+func (rs *BlackjackGameRules) endRound(deck core.Shoe) {
+	deck.EndRound()
+	if deck.ContinuousShuffle() {
+		rs.TrackingStrategy.Shuffle()
+	}
+}
+
+func (rs *BlackjackGameRules) cutCard() core.CutCard {
+	cut := core.CutCard{Penetration: rs.Penetration, StdDev: rs.CutCardStdDev, ExtraRounds: rs.CutCardExtraRounds}
+	if rs.PenetrationDealt > 0 {
+		cut.Penetration = rs.PenetrationDealt
+		cut.PercentDealt = true
+	}
+	return cut
+}
+
+// instanceStrategy copies the tracking strategy for a thread, telling it the
+// composition of the decks when it counts off them
+func (rs *BlackjackGameRules) instanceStrategy() strategies.TrackingStrategy {
+	strategy := rs.TrackingStrategy.Instance()
+	if aware, ok := strategy.(strategies.DeckAware); ok {
+		aware.SetDeck(rs.deck())
+	}
+	return strategy
+}
@@ -0,0 +1,162 @@
+package blackjack
+
+import "github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+
+// Bonuses are paid in place of even money on a winning 21. Payouts are to one,
+// a hand is paid the best bonus it qualifies for
+type Bonuses struct {
+	FiveCard  float32 // 21 in five cards
+	SixCard   float32 // 21 in six cards
+	SevenCard float32 // 21 in seven or more cards
+	Mixed     float32 // 6-7-8 or 7-7-7 of mixed suits
+	Suited    float32 // 6-7-8 or 7-7-7 in one suit
+	Spades    float32 // 6-7-8 or 7-7-7 in spades
+	Doubled   bool    // paid on doubled hands
+	Split     bool    // paid on split hands
+}
+
+// Spanish21Bonuses are the usual Spanish 21 bonuses, not paid after doubling
+var Spanish21Bonuses = Bonuses{
+	FiveCard:  1.5,
+	SixCard:   2,
+	SevenCard: 3,
+	Mixed:     1.5,
+	Suited:    2,
+	Spades:    3,
+	Split:     true,
+}
+
+// Payout is the bonus the hand is paid if it wins, 0 when it isn't a bonus hand
+func (b *Bonuses) Payout(hand core.Hand) float32 {
+	if total, _ := hand.HandValue(); total != 21 || (hand.Doubled && !b.Doubled) ||
+		((hand.SplitHand || hand.SplitAcesHand) && !b.Split) {
+		return 0
+	}
+	switch cards := len(hand.Cards); {
+	case cards >= 7:
+		return b.SevenCard
+	case cards == 6:
+		return b.SixCard
+	case cards == 5:
+		return b.FiveCard
+	case cards == 3:
+		return b.threeCard(hand)
+	}
+	return 0
+}
+
+// threeCard pays a 6-7-8 or 7-7-7 by its suits
+func (b *Bonuses) threeCard(hand core.Hand) float32 {
+	sixes, sevens, eights := 0, 0, 0
+	suited, spades := true, true
+	for _, c := range hand.Cards {
+		switch c.Value {
+		case 6:
+			sixes++
+		case 7:
+			sevens++
+		case 8:
+			eights++
+		}
+		suited = suited && c.Suit != core.SuitUnknown && c.Suit == hand.Cards[0].Suit
+		spades = spades && c.Suit == core.SuitSpades
+	}
+	if sevens != 3 && (sixes != 1 || sevens != 1 || eights != 1) {
+		return 0
+	}
+	switch {
+	case spades:
+		return b.Spades
+	case suited:
+		return b.Suited
+	}
+	return b.Mixed
+}
+
+// RescueRule lists the upcards a doubled total is given up against
+type RescueRule struct {
+	PlayerValue  int
+	DealerUpcard []int
+}
+
+// NewSpanish21Game sets up Spanish 21, dealt from 48 card decks with the 10s
+// taken out. The player gets back the edge through a 21 always winning, late
+// surrender, double rescue, doubling on any number of cards and the bonuses
+func NewSpanish21Game() *BlackjackGameRules {
+	return NewBlackjackGameRules(InitSpanish21()).
+		SetDeck(core.SpanishDeck).
+		SetResplitAces(true).
+		SetPlayer21Wins(true).
+		SetDoubleAnyCards(true).
+		SetLateSurrender(true).
+		SetDoubleRescue(true).
+		SetBonuses(&Spanish21Bonuses)
+}
+
+// InitSpanish21 is basic strategy for Spanish 21, including when to rescue a double
+func InitSpanish21() *Ruleset {
+	ruleset := InitGame(Spanish21Rules, Spanish21Splits)
+	ruleset.rescues = SplitMap{}
+	for _, v := range Spanish21Rescues {
+		for _, dealerCard := range v.DealerUpcard {
+			ruleset.rescues[HashSplit(v.PlayerValue, dealerCard)] = struct{}{}
+		}
+	}
+	return ruleset
+}
+
+func (rs *BlackjackGameRules) canDouble(hand core.Hand) bool {
+	return hand.CanDouble() || (rs.DoubleAnyCards && len(hand.Cards) > 2 && !hand.SplitAcesHand)
+}
+
+func (rs *BlackjackGameRules) canSurrender(hand core.Hand) bool {
+	return rs.LateSurrender && len(hand.Cards) == 2 && !hand.SplitHand && !hand.SplitAcesHand
+}
+
+// rescue is true when a doubled hand should be given up. Standing is all that's
+// left after a double, so the table goes by total alone
+func (rs *BlackjackGameRules) rescue(hand core.Hand, dealerUpcard core.Card) bool {
+	if !rs.DoubleRescue || !hand.Doubled {
+		return false
+	}
+	total, _ := hand.HandValue()
+	if total > 21 {
+		return false
+	}
+	_, exists := rs.playerStrategy.rescues[HashSplit(total, dealerUpcard.Value)]
+	return exists
+}
+
+// HandResult settles a hand under the rules, CalculateHandResult with surrender,
+// the player's 21 winning and the bonuses on top
+func (rs *BlackjackGameRules) HandResult(playerHand core.Hand, dealerHand core.Hand, bid float32) core.HandResult {
+	if playerHand.Surrendered {
+		if playerHand.Doubled {
+			// the double is taken back, the original bet is lost
+			return core.MakeHandResult(core.HandResultRescue, -bid)
+		}
+		return core.MakeHandResult(core.HandResultSurrender, -bid/2)
+	}
+	result := CalculateHandResult(playerHand, dealerHand, bid)
+	if playerValue, _ := playerHand.HandValue(); playerValue != 21 {
+		return result
+	}
+	stake := bid
+	if playerHand.Doubled {
+		stake *= 2
+	}
+	if rs.Player21Wins {
+		switch result.Result {
+		case core.HandResultBlackjackPush:
+			return core.MakeHandResult(core.HandResultBlackjack, bid*blackjackPayout)
+		case core.HandResultPush:
+			result = core.MakeHandResult(core.HandResultWin, stake)
+		}
+	}
+	if result.Result == core.HandResultWin && rs.Bonuses != nil {
+		if payout := rs.Bonuses.Payout(playerHand); payout > 0 {
+			return core.MakeHandResult(core.HandResultBonus, stake*payout)
+		}
+	}
+	return result
+}
@@ -0,0 +1,289 @@
+package blackjack
+
+// Spanish21Rules is H17 basic strategy for a 48 card deck, with a player 21
+// always winning, late surrender and double rescue. Player totals below 9 hit.
+// The table goes by total alone, an approximation of the full strategy which
+// also changes with the number of cards in the hand, e.g. hitting a multi-card
+// 14 against a 4 and not doubling a 3+ card 10 or 11 against a high upcard
+var Spanish21Rules = RulesMap{
+	2: {map[bool]map[int]PlayerAction{
+		false: { // hard
+			9:  PlayerActionHit,
+			10: PlayerActionDoubleOrHit,
+			11: PlayerActionDoubleOrHit,
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionStand,
+			16: PlayerActionStand,
+			17: PlayerActionStand,
+			18: PlayerActionStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}, true: { // soft
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionHit,
+			16: PlayerActionHit,
+			17: PlayerActionHit,
+			18: PlayerActionStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}}},
+	3: {map[bool]map[int]PlayerAction{
+		false: { // hard
+			9:  PlayerActionHit,
+			10: PlayerActionDoubleOrHit,
+			11: PlayerActionDoubleOrHit,
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionStand,
+			16: PlayerActionStand,
+			17: PlayerActionStand,
+			18: PlayerActionStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}, true: { // soft
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionHit,
+			16: PlayerActionHit,
+			17: PlayerActionHit,
+			18: PlayerActionStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}}},
+	4: {map[bool]map[int]PlayerAction{
+		false: { // hard
+			9:  PlayerActionHit,
+			10: PlayerActionDoubleOrHit,
+			11: PlayerActionDoubleOrHit,
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionStand,
+			15: PlayerActionStand,
+			16: PlayerActionStand,
+			17: PlayerActionStand,
+			18: PlayerActionStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}, true: { // soft
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionHit,
+			16: PlayerActionHit,
+			17: PlayerActionDoubleOrHit,
+			18: PlayerActionDoubleOrStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}}},
+	5: {map[bool]map[int]PlayerAction{
+		false: { // hard
+			9:  PlayerActionHit,
+			10: PlayerActionDoubleOrHit,
+			11: PlayerActionDoubleOrHit,
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionStand,
+			15: PlayerActionStand,
+			16: PlayerActionStand,
+			17: PlayerActionStand,
+			18: PlayerActionStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}, true: { // soft
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionHit,
+			16: PlayerActionDoubleOrHit,
+			17: PlayerActionDoubleOrHit,
+			18: PlayerActionDoubleOrStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}}},
+	6: {map[bool]map[int]PlayerAction{
+		false: { // hard
+			9:  PlayerActionDoubleOrHit,
+			10: PlayerActionDoubleOrHit,
+			11: PlayerActionDoubleOrHit,
+			12: PlayerActionHit,
+			13: PlayerActionStand,
+			14: PlayerActionStand,
+			15: PlayerActionStand,
+			16: PlayerActionStand,
+			17: PlayerActionStand,
+			18: PlayerActionStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}, true: { // soft
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionDoubleOrHit,
+			16: PlayerActionDoubleOrHit,
+			17: PlayerActionDoubleOrHit,
+			18: PlayerActionDoubleOrStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}}},
+	7: {map[bool]map[int]PlayerAction{
+		false: { // hard
+			9:  PlayerActionHit,
+			10: PlayerActionDoubleOrHit,
+			11: PlayerActionDoubleOrHit,
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionHit,
+			16: PlayerActionHit,
+			17: PlayerActionStand,
+			18: PlayerActionStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}, true: { // soft
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionHit,
+			16: PlayerActionHit,
+			17: PlayerActionHit,
+			18: PlayerActionStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}}},
+	8: {map[bool]map[int]PlayerAction{
+		false: { // hard
+			9:  PlayerActionHit,
+			10: PlayerActionDoubleOrHit,
+			11: PlayerActionDoubleOrHit,
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionHit,
+			16: PlayerActionHit,
+			17: PlayerActionStand,
+			18: PlayerActionStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}, true: { // soft
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionHit,
+			16: PlayerActionHit,
+			17: PlayerActionHit,
+			18: PlayerActionStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}}},
+	9: {map[bool]map[int]PlayerAction{
+		false: { // hard
+			9:  PlayerActionHit,
+			10: PlayerActionHit,
+			11: PlayerActionDoubleOrHit,
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionHit,
+			16: PlayerActionHit,
+			17: PlayerActionStand,
+			18: PlayerActionStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}, true: { // soft
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionHit,
+			16: PlayerActionHit,
+			17: PlayerActionHit,
+			18: PlayerActionHit,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}}},
+	10: {map[bool]map[int]PlayerAction{
+		false: { // hard
+			9:  PlayerActionHit,
+			10: PlayerActionHit,
+			11: PlayerActionDoubleOrHit,
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionHit,
+			16: PlayerActionHit,
+			17: PlayerActionStand,
+			18: PlayerActionStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}, true: { // soft
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionHit,
+			16: PlayerActionHit,
+			17: PlayerActionHit,
+			18: PlayerActionHit,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}}},
+	11: {map[bool]map[int]PlayerAction{
+		false: { // hard
+			9:  PlayerActionHit,
+			10: PlayerActionHit,
+			11: PlayerActionDoubleOrHit,
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionHit,
+			16: PlayerActionSurrenderOrHit,
+			17: PlayerActionSurrenderOrStand,
+			18: PlayerActionStand,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}, true: { // soft
+			12: PlayerActionHit,
+			13: PlayerActionHit,
+			14: PlayerActionHit,
+			15: PlayerActionHit,
+			16: PlayerActionHit,
+			17: PlayerActionHit,
+			18: PlayerActionHit,
+			19: PlayerActionStand,
+			20: PlayerActionStand,
+		}}},
+}
+
+var Spanish21Splits = []SplitRule{
+	{PlayerCard: 11, DealerUpcard: []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 11}},
+	{PlayerCard: 10, DealerUpcard: []int{}},
+	{PlayerCard: 9, DealerUpcard: []int{3, 4, 5, 6, 8, 9}},
+	{PlayerCard: 8, DealerUpcard: []int{2, 3, 4, 5, 6, 7, 8, 9, 10}},
+	{PlayerCard: 7, DealerUpcard: []int{2, 3, 4, 5, 6, 7}},
+	{PlayerCard: 6, DealerUpcard: []int{4, 5, 6}},
+	{PlayerCard: 5, DealerUpcard: []int{}},
+	{PlayerCard: 4, DealerUpcard: []int{}},
+	{PlayerCard: 3, DealerUpcard: []int{2, 3, 4, 5, 6, 7, 8}},
+	{PlayerCard: 2, DealerUpcard: []int{2, 3, 4, 5, 6, 7, 8}},
+}
+
+// Spanish21Rescues are the doubled totals given up against each upcard, where
+// standing loses more than half the doubled bet
+var Spanish21Rescues = []RescueRule{
+	{PlayerValue: 6, DealerUpcard: []int{8, 9, 10, 11}},
+	{PlayerValue: 7, DealerUpcard: []int{8, 9, 10, 11}},
+	{PlayerValue: 8, DealerUpcard: []int{8, 9, 10, 11}},
+	{PlayerValue: 9, DealerUpcard: []int{8, 9, 10, 11}},
+	{PlayerValue: 10, DealerUpcard: []int{8, 9, 10, 11}},
+	{PlayerValue: 11, DealerUpcard: []int{8, 9, 10, 11}},
+	{PlayerValue: 12, DealerUpcard: []int{8, 9, 10, 11}},
+	{PlayerValue: 13, DealerUpcard: []int{8, 9, 10, 11}},
+	{PlayerValue: 14, DealerUpcard: []int{8, 9, 10, 11}},
+	{PlayerValue: 15, DealerUpcard: []int{8, 9, 10, 11}},
+	{PlayerValue: 16, DealerUpcard: []int{8, 9, 10, 11}},
+	{PlayerValue: 17, DealerUpcard: []int{11}},
+}
@@ -0,0 +1,115 @@
+package blackjack
+
+import (
+	"testing"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+)
+
+func parseHand(t *testing.T, script string) core.Hand {
+	t.Helper()
+	cards, err := core.ParseCards(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return core.Hand{Cards: cards}
+}
+
+func TestSpanish21HandResult(t *testing.T) {
+	rules := NewSpanish21Game()
+	doubled := func(h core.Hand) core.Hand {
+		h.Doubled = true
+		return h
+	}
+	surrendered := func(h core.Hand) core.Hand {
+		h.Surrendered = true
+		return h
+	}
+	tests := []struct {
+		Name     string
+		Player   core.Hand
+		Dealer   core.Hand
+		Expected core.HandResult
+	}{
+		{Name: "21 beats 21", Player: parseHand(t, "Kh 5c 6d"), Dealer: parseHand(t, "Qs 4h 7c"),
+			Expected: core.MakeHandResult(core.HandResultWin, 10)},
+		{Name: "blackjack beats blackjack", Player: parseHand(t, "As Kh"), Dealer: parseHand(t, "Ac Qd"),
+			Expected: core.MakeHandResult(core.HandResultBlackjack, 15)},
+		{Name: "surrender", Player: surrendered(parseHand(t, "Kh 6c")), Dealer: parseHand(t, "Qs 8h"),
+			Expected: core.MakeHandResult(core.HandResultSurrender, -5)},
+		{Name: "rescue", Player: surrendered(doubled(parseHand(t, "5h 4c 3d"))), Dealer: parseHand(t, "Qs 8h"),
+			Expected: core.MakeHandResult(core.HandResultRescue, -10)},
+		{Name: "five card 21", Player: parseHand(t, "2h 3c 4d 5s 7h"), Dealer: parseHand(t, "Qs 8h"),
+			Expected: core.MakeHandResult(core.HandResultBonus, 15)},
+		{Name: "seven card 21", Player: parseHand(t, "2h 2c 3d 3s 4h 4c 3h"), Dealer: parseHand(t, "Qs 8h"),
+			Expected: core.MakeHandResult(core.HandResultBonus, 30)},
+		{Name: "mixed 6-7-8", Player: parseHand(t, "6h 7c 8d"), Dealer: parseHand(t, "Qs 8h"),
+			Expected: core.MakeHandResult(core.HandResultBonus, 15)},
+		{Name: "suited 7-7-7", Player: parseHand(t, "7d 7d 7d"), Dealer: parseHand(t, "Qs 8h"),
+			Expected: core.MakeHandResult(core.HandResultBonus, 20)},
+		{Name: "spade 6-7-8", Player: parseHand(t, "8s 6s 7s"), Dealer: parseHand(t, "Qs 8h"),
+			Expected: core.MakeHandResult(core.HandResultBonus, 30)},
+		{Name: "no bonus after doubling", Player: doubled(parseHand(t, "6h 7c 8d")), Dealer: parseHand(t, "Qs 8h"),
+			Expected: core.MakeHandResult(core.HandResultWin, 20)},
+		{Name: "bonus hand that loses", Player: parseHand(t, "6h 7c 8d"), Dealer: parseHand(t, "Ac Kd"),
+			Expected: core.MakeHandResult(core.HandResultDealerBlackjack, -10)},
+	}
+	for _, test := range tests {
+		ExpectHandResult(t, rules.HandResult(test.Player, test.Dealer, 10), test.Expected, test.Name)
+	}
+
+	// the standard game settles 21s as before
+	standard := MakeTestRules()
+	ExpectHandResult(t, standard.HandResult(parseHand(t, "Kh 5c 6d"), parseHand(t, "Qs 4h 7c"), 10),
+		core.MakeHandResult(core.HandResultPush, 0), "standard 21 vs 21")
+	ExpectHandResult(t, standard.HandResult(parseHand(t, "6h 7c 8d"), parseHand(t, "Qs 8h"), 10),
+		core.MakeHandResult(core.HandResultWin, 10), "standard 6-7-8")
+}
+
+func TestSpanish21Decisions(t *testing.T) {
+	rules := NewSpanish21Game()
+	tests := []struct {
+		Hand     core.Hand
+		Upcard   int
+		Expected PlayerDecision
+	}{
+		{Hand: MakeHand(10, 6), Upcard: 11, Expected: PlayerDecisionSurrender},
+		{Hand: MakeHand(4, 5, 7), Upcard: 11, Expected: PlayerDecisionHit}, // surrender is on two cards only
+		{Hand: MakeHand(10, 7), Upcard: 11, Expected: PlayerDecisionSurrender},
+		{Hand: MakeHand(10, 7), Upcard: 10, Expected: PlayerDecisionStand},
+		{Hand: MakeHand(4, 3, 10), Upcard: 11, Expected: PlayerDecisionStand}, // a 17 that can't surrender stands
+		{Hand: MakeHand(2, 4, 5), Upcard: 10, Expected: PlayerDecisionDouble}, // double on any number of cards
+		{Hand: MakeHand(10, 2), Upcard: 4, Expected: PlayerDecisionHit},       // no 10s to bust the dealer
+		{Hand: MakeHand(8, 8), Upcard: 11, Expected: PlayerDecisionSurrender},
+	}
+	for _, test := range tests {
+		if d := rules.MakePlayerDecision(test.Hand, core.Card{Value: test.Upcard}, 0); d != test.Expected {
+			t.Errorf("%s vs %d should %s, got %s", test.Hand.ToString(), test.Upcard, test.Expected.ToString(), d.ToString())
+		}
+	}
+
+	// a doubled 14 is given up against a 10 but stood against a 6
+	hand := MakeHand(5, 4, 5)
+	hand.Doubled = true
+	if !rules.rescue(hand, core.Card{Value: 10}) {
+		t.Errorf("doubled 14 vs 10 should be rescued")
+	}
+	if rules.rescue(hand, core.Card{Value: 6}) {
+		t.Errorf("doubled 14 vs 6 should stand")
+	}
+	if MakeTestRules().rescue(hand, core.Card{Value: 10}) {
+		t.Errorf("no rescue in a standard game")
+	}
+}
+
+func TestSpanish21Round(t *testing.T) {
+	rules := NewSpanish21Game()
+	// player 5/6, dealer 9 up. The player doubles into 3 and rescues 14, with no
+	// hand left in play the dealer doesn't draw to 16
+	deck := scriptedDeck(t, "5h 7c 6d 9s 3c")
+	results := PlayRound(deck, rules, 1)
+	ExpectHandResult(t, results[0], core.MakeHandResult(core.HandResultRescue, -1), "rescued 5/6/3")
+	if deck.Remaining() != 0 {
+		t.Fatalf("expected every card dealt, %d left", deck.Remaining())
+	}
+}
@@ -0,0 +1,162 @@
+package blackjack
+
+import (
+	"math"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+)
+
+// TeamParams describes a big player team, a flat betting spotter counts each
+// table and calls the big player in once the count is good
+type TeamParams struct {
+	Tables       int
+	SpotterUnits float32 // the spotters' flat bet
+	CallInTC     int     // count a spotter calls the big player in at
+	LeaveTC      int     // the big player leaves once the count drops below this
+	TravelRounds int     // rounds played at the table before the big player gets there
+}
+
+type TeamResults struct {
+	Rounds         int // rounds played at each table
+	Shoes          int
+	SpotterHands   int
+	BigPlayerHands int
+	CallIns        int
+	SpotterEV      float32
+	BigPlayerEV    float32
+	HourlyAVs      []float32 // the team's combined result for each hour of play
+}
+
+func (r TeamResults) EV() float32 {
+	return r.SpotterEV + r.BigPlayerEV
+}
+
+// Hourly is the mean and standard deviation of the team's hourly result
+func (r TeamResults) Hourly() (float32, float32) {
+	if len(r.HourlyAVs) == 0 {
+		return 0, 0
+	}
+	mean := float32(0)
+	for _, av := range r.HourlyAVs {
+		mean += av
+	}
+	mean /= float32(len(r.HourlyAVs))
+	variance := float32(0)
+	for _, av := range r.HourlyAVs {
+		variance += (av - mean) * (av - mean)
+	}
+	return mean, float32(math.Sqrt(float64(variance / float32(len(r.HourlyAVs)))))
+}
+
+func AggregateTeamResults(results ...TeamResults) TeamResults {
+	aggregated := TeamResults{}
+	for _, r := range results {
+		aggregated.Rounds += r.Rounds
+		aggregated.Shoes += r.Shoes
+		aggregated.SpotterHands += r.SpotterHands
+		aggregated.BigPlayerHands += r.BigPlayerHands
+		aggregated.CallIns += r.CallIns
+		aggregated.SpotterEV += r.SpotterEV
+		aggregated.BigPlayerEV += r.BigPlayerEV
+		aggregated.HourlyAVs = append(aggregated.HourlyAVs, r.HourlyAVs...)
+	}
+	return aggregated
+}
+
+type teamTable struct {
+	deck  core.Shoe
+	rules BlackjackGameRules
+}
+
+// the big player isn't at a table
+const bigPlayerIdle = -1
+
+// PlayTeam plays the tables in lockstep until `shoes` shoes have been dealt
+// between them. Every table has its own shoe and its own instance of the
+// tracking strategy, which the big player bets off once called in. Errors when
+// a shoe runs out of cards
+func PlayTeam(rules BlackjackGameRules, decks int, shoes int, params TeamParams, roundsPerHour float32) (results TeamResults, err error) {
+	defer recoverOutOfCards(&err)
+	tables := make([]*teamTable, params.Tables)
+	for i := range tables {
+		table := &teamTable{rules: rules}
+		if rules.Seed != 0 {
+			// keep the tables' shoes apart from the other threads' seeds
+			table.rules.Seed = rules.Seed + uint64(i)*7919
+		}
+		table.deck = table.rules.dealer(decks, table.rules.Seed)
+		table.rules.TrackingStrategy = rules.instanceStrategy()
+		table.rules.Errors = rules.Errors.Instance(table.rules.Seed)
+		table.rules.Camouflage = nil
+		table.rules.HoleCard = rules.HoleCard.Instance(table.rules.Seed)
+		strategy, errors := table.rules.TrackingStrategy, table.rules.Errors
+		if errors != nil {
+			errors.Attach(strategy)
+		}
+		table.deck.SetPreviewCard(func(c core.Card) {
+			strategy.Update(c)
+			if errors != nil {
+				errors.Count(strategy)
+			}
+		})
+		tables[i] = table
+	}
+
+	bigPlayer := bigPlayerIdle
+	travel := 0
+	hourAV := float32(0)
+	perHour := int(roundsPerHour)
+	for results.Shoes < shoes {
+		for t, table := range tables {
+			tc := table.rules.TrackingStrategy.TrueCount(table.deck)
+			if bigPlayer == t && travel == 0 && tc < params.LeaveTC {
+				bigPlayer = bigPlayerIdle
+			}
+			if bigPlayer == bigPlayerIdle && tc >= params.CallInTC {
+				bigPlayer = t
+				travel = params.TravelRounds
+				results.CallIns++
+			}
+
+			bets := []float32{table.rules.PlaceBet(params.SpotterUnits)}
+			playing := bigPlayer == t && travel == 0
+			if playing {
+				bid := table.rules.TrackingStrategy.Bid(table.deck)
+				bets = append(bets, table.rules.PlaceBet(bid.Units))
+			}
+			seats := PlaySeats(table.deck, &table.rules, bets)
+			for _, r := range seats[0] {
+				results.SpotterEV += r.AV
+				hourAV += r.AV
+			}
+			results.SpotterHands++
+			if playing {
+				for _, r := range seats[1] {
+					results.BigPlayerEV += r.AV
+					hourAV += r.AV
+				}
+				results.BigPlayerHands++
+			}
+
+			table.rules.endRound(table.deck)
+			if table.rules.shoeFinished(table.deck) {
+				table.rules.TrackingStrategy.Shuffle()
+				table.deck.Reshuffle()
+				results.Shoes++
+				if bigPlayer == t {
+					// whether there or on the way, the big player walks off at the shuffle
+					bigPlayer = bigPlayerIdle
+				}
+			}
+		}
+		if travel > 0 {
+			travel--
+		}
+		results.Rounds++
+		if perHour > 0 && results.Rounds%perHour == 0 {
+			results.HourlyAVs = append(results.HourlyAVs, hourAV)
+			hourAV = 0
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,51 @@
+package blackjack
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/core"
+	"github.com/onemorebsmith/blackjack-solver/src/blackjack/strategies"
+)
+
+func TestPlayTeam(t *testing.T) {
+	rules := MakeTestRules().SetPenetration(1.5)
+	rules.TrackingStrategy = strategies.InitHighLow(map[int]strategies.BidStrategy{0: {Hands: 1, Units: 1}, 2: {Hands: 1, Units: 8}})
+	params := TeamParams{Tables: 3, SpotterUnits: 1, CallInTC: 2, LeaveTC: 1, TravelRounds: 2}
+
+	results, err := PlayTeam(*rules, 6, 60, params, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.Shoes < 60 {
+		t.Fatalf("expected at least 60 shoes, got %d", results.Shoes)
+	}
+	if results.SpotterHands != results.Rounds*params.Tables {
+		t.Fatalf("every spotter should play every round, got %d hands over %d rounds", results.SpotterHands, results.Rounds)
+	}
+	if results.CallIns == 0 || results.BigPlayerHands == 0 {
+		t.Fatalf("expected the big player to be called in, got %d call ins", results.CallIns)
+	}
+	if results.BigPlayerHands > results.Rounds {
+		t.Fatalf("the big player can only play one table at a time")
+	}
+
+	params.CallInTC = 100
+	results, err = PlayTeam(*rules, 6, 60, params, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.CallIns != 0 || results.BigPlayerHands != 0 || results.BigPlayerEV != 0 {
+		t.Fatalf("the big player should never be called in")
+	}
+}
+
+func TestPlayTeamOutOfCards(t *testing.T) {
+	// dealt to the last card, a round will run the single deck out
+	rules := MakeTestRules().SetPenetration(0)
+	rules.TrackingStrategy = strategies.InitFlatbetStrategy()
+	params := TeamParams{Tables: 3, SpotterUnits: 1, CallInTC: 2, LeaveTC: 1}
+	if _, err := PlayTeam(*rules, 1, 10, params, 100); !errors.Is(err, core.ErrOutOfCards) {
+		t.Fatalf("expected out of cards, got %v", err)
+	}
+}